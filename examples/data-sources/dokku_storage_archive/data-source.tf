@@ -0,0 +1,8 @@
+data "dokku_storage_archive" "uploads" {
+  name        = "uploads"
+  output_path = "${path.module}/backups/uploads.tar"
+}
+
+output "uploads_sha256" {
+  value = data.dokku_storage_archive.uploads.output_sha256
+}
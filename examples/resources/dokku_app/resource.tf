@@ -8,6 +8,63 @@ resource "dokku_app" "demo2" {
   config = {
     foo = "bar"
   }
+  sensitive_config = {
+    SECRET_KEY = "change-me"
+  }
+
+  scale = {
+    web    = 2
+    worker = 1
+  }
+
+  build = {
+    builder         = "dockerfile"
+    dockerfile_path = "docker/Dockerfile.prod"
+  }
+
+  nginx = {
+    client_max_body_size = "50m"
+    proxy_read_timeout   = "120s"
+  }
+
+  process = {
+    restart_policy       = "unless-stopped"
+    stop_timeout_seconds = 60
+  }
+
+  logs = {
+    vector_sink = "loki://?endpoint=http://loki:3100&encoding[codec]=json&labels[app]=demo"
+    max_size    = "10m"
+  }
+
+  scheduler = {
+    selected = "docker-local"
+    docker_local = {
+      init_process = true
+    }
+  }
+
+  git = {
+    deploy_branch = "main"
+    keep_git_dir  = true
+  }
+
+  resources = {
+    default = {
+      limit = {
+        memory = "512m"
+      }
+    }
+    web = {
+      limit = {
+        cpu    = "1"
+        memory = "1g"
+      }
+      reserve = {
+        memory = "256m"
+      }
+    }
+  }
 
   storage = {
     uploads = {
@@ -16,10 +73,28 @@ resource "dokku_app" "demo2" {
     "/var/log" = {
       mount_path = "/var/log"
     }
+    static = {
+      mount_path    = "/app/static"
+      read_only     = true
+      mount_options = ["z"]
+    }
     config = {
       mount_path      = "/app/config"
       local_directory = "./config"
     }
+    nginx = {
+      mount_path = "/etc/nginx/conf.d"
+      files = {
+        "default.conf" = {
+          content = file("./nginx/default.conf")
+        }
+        "certs/bundle.pem" = {
+          content_base64 = filebase64("./certs/bundle.pem")
+          mode           = "0600"
+          owner          = "0:0"
+        }
+      }
+    }
   }
 
   # DEPRECATED use ports instead
@@ -0,0 +1,2 @@
+# dokku_certificate can be imported by specifying the app name
+terraform import dokku_certificate.demo app_name
@@ -0,0 +1,5 @@
+resource "dokku_certificate" "demo" {
+  app_name    = "demo"
+  certificate = file("./certs/demo.crt")
+  private_key = file("./certs/demo.key")
+}
@@ -0,0 +1,6 @@
+resource "dokku_logs" "global" {
+  vector_image   = "timberio/vector:0.38.0-debian"
+  vector_sink    = "loki://?endpoint=http://loki:3100&encoding[codec]=json&labels[source]=dokku"
+  max_size       = "50m"
+  vector_running = true
+}
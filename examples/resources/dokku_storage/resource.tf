@@ -0,0 +1,24 @@
+resource "dokku_storage" "assets" {
+  name  = "shared-assets"
+  chown = "heroku"
+
+  local_directory = "./assets"
+
+  files = {
+    "robots.txt" = {
+      content = "User-agent: *\nDisallow:\n"
+    }
+  }
+
+  delete_on_destroy = true
+}
+
+resource "dokku_app" "web" {
+  app_name = "web"
+
+  storage = {
+    (dokku_storage.assets.name) = {
+      mount_path = "/app/public/assets"
+    }
+  }
+}
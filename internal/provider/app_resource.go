@@ -5,16 +5,23 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	dokkuclient "terraform-provider-dokku/internal/provider/dokku_client"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -27,6 +34,7 @@ var (
 	_ resource.ResourceWithConfigure      = &appResource{}
 	_ resource.ResourceWithImportState    = &appResource{}
 	_ resource.ResourceWithValidateConfig = &appResource{}
+	_ resource.ResourceWithModifyPlan     = &appResource{}
 )
 
 func NewAppResource() resource.Resource {
@@ -38,25 +46,53 @@ type appResource struct {
 }
 
 type appResourceModel struct {
-	AppName       types.String                 `tfsdk:"app_name"`
-	Config        map[string]types.String      `tfsdk:"config"`
-	Storage       map[string]storageModel      `tfsdk:"storage"`
-	Checks        *checkModel                  `tfsdk:"checks"`
-	Ports         map[string]portModel         `tfsdk:"ports"`
-	ProxyPorts    map[string]portModel         `tfsdk:"proxy_ports"`
-	Domains       []types.String               `tfsdk:"domains"`
-	DockerOptions map[string]dockerOptionModel `tfsdk:"docker_options"`
-	Networks      *networkModel                `tfsdk:"networks"`
-	Deploy        *deployModel                 `tfsdk:"deploy"`
+	AppName         types.String                 `tfsdk:"app_name"`
+	CloneFrom       types.String                 `tfsdk:"clone_from"`
+	CloneSkipDeploy types.Bool                   `tfsdk:"clone_skip_deploy"`
+	Config          map[string]types.String      `tfsdk:"config"`
+	ConfigMode      types.String                 `tfsdk:"config_mode"`
+	SensitiveConfig map[string]types.String      `tfsdk:"sensitive_config"`
+	Storage         map[string]storageModel      `tfsdk:"storage"`
+	Checks          *checkModel                  `tfsdk:"checks"`
+	Ports           map[string]portModel         `tfsdk:"ports"`
+	ProxyPorts      map[string]portModel         `tfsdk:"proxy_ports"`
+	Domains         []types.String               `tfsdk:"domains"`
+	DockerOptions   map[string]dockerOptionModel `tfsdk:"docker_options"`
+	Networks        *networkModel                `tfsdk:"networks"`
+	Deploy          *deployModel                 `tfsdk:"deploy"`
+	Scale           map[string]types.Int64       `tfsdk:"scale"`
+	Resources       map[string]resourcesModel    `tfsdk:"resources"`
+	Build           *buildModel                  `tfsdk:"build"`
+	Buildpacks      []types.String               `tfsdk:"buildpacks"`
+	Nginx           *nginxModel                  `tfsdk:"nginx"`
+	Proxy           *proxyModel                  `tfsdk:"proxy"`
+	Process         *processModel                `tfsdk:"process"`
+	Logs            *logsModel                   `tfsdk:"logs"`
+	Scheduler       *schedulerModel              `tfsdk:"scheduler"`
+	Git             *gitModel                    `tfsdk:"git"`
+	Running         types.Bool                   `tfsdk:"running"`
+	LockDuringApply types.Bool                   `tfsdk:"lock_during_apply"`
+
+	DeployedImage    types.String `tfsdk:"deployed_image"`
+	DeployedRevision types.String `tfsdk:"deployed_revision"`
 }
 
 type storageModel struct {
-	LocalDirectory types.String `tfsdk:"local_directory"`
-	MountPath      types.String `tfsdk:"mount_path"`
+	LocalDirectory types.String                `tfsdk:"local_directory"`
+	MountPath      types.String                `tfsdk:"mount_path"`
+	ReadOnly       types.Bool                  `tfsdk:"read_only"`
+	MountOptions   types.Set                   `tfsdk:"mount_options"`
+	Files          map[string]storageFileModel `tfsdk:"files"`
 }
 
 type checkModel struct {
-	Status types.String `tfsdk:"status"`
+	Status       types.String   `tfsdk:"status"`
+	Disabled     []types.String `tfsdk:"disabled"`
+	Skipped      []types.String `tfsdk:"skipped"`
+	WaitToRetire types.Int64    `tfsdk:"wait_to_retire"`
+	Attempts     types.Int64    `tfsdk:"attempts"`
+	Timeout      types.Int64    `tfsdk:"timeout"`
+	Wait         types.Int64    `tfsdk:"wait"`
 }
 
 type portModel struct {
@@ -74,6 +110,90 @@ type networkModel struct {
 	InitialNetwork   types.String `tfsdk:"initial_network"`
 }
 
+type buildModel struct {
+	Builder          types.String `tfsdk:"builder"`
+	BuildDir         types.String `tfsdk:"build_dir"`
+	DockerfilePath   types.String `tfsdk:"dockerfile_path"`
+	ProjecttomlPath  types.String `tfsdk:"projecttoml_path"`
+	NixpackstomlPath types.String `tfsdk:"nixpackstoml_path"`
+	NixpacksNoCache  types.Bool   `tfsdk:"nixpacks_no_cache"`
+}
+
+type nginxModel struct {
+	ClientMaxBodySize     types.String `tfsdk:"client_max_body_size"`
+	ProxyConnectTimeout   types.String `tfsdk:"proxy_connect_timeout"`
+	ProxyReadTimeout      types.String `tfsdk:"proxy_read_timeout"`
+	ProxySendTimeout      types.String `tfsdk:"proxy_send_timeout"`
+	Hsts                  types.Bool   `tfsdk:"hsts"`
+	HstsIncludeSubdomains types.Bool   `tfsdk:"hsts_include_subdomains"`
+	HstsMaxAge            types.Int64  `tfsdk:"hsts_max_age"`
+	HstsPreload           types.Bool   `tfsdk:"hsts_preload"`
+	AccessLogFormat       types.String `tfsdk:"access_log_format"`
+	AccessLogPath         types.String `tfsdk:"access_log_path"`
+	ErrorLogPath          types.String `tfsdk:"error_log_path"`
+	BindAddressIpv4       types.String `tfsdk:"bind_address_ipv4"`
+	BindAddressIpv6       types.String `tfsdk:"bind_address_ipv6"`
+	XForwardedForValue    types.String `tfsdk:"x_forwarded_for_value"`
+	XForwardedPortValue   types.String `tfsdk:"x_forwarded_port_value"`
+	XForwardedProtoValue  types.String `tfsdk:"x_forwarded_proto_value"`
+	XForwardedSsl         types.String `tfsdk:"x_forwarded_ssl"`
+}
+
+type proxyModel struct {
+	Type       types.String            `tfsdk:"type"`
+	Enabled    types.Bool              `tfsdk:"enabled"`
+	Labels     map[string]types.String `tfsdk:"labels"`
+	Properties map[string]types.String `tfsdk:"properties"`
+}
+
+type processModel struct {
+	RestartPolicy      types.String `tfsdk:"restart_policy"`
+	StopTimeoutSeconds types.Int64  `tfsdk:"stop_timeout_seconds"`
+	ProcfilePath       types.String `tfsdk:"procfile_path"`
+}
+
+type logsModel struct {
+	VectorSink types.String `tfsdk:"vector_sink"`
+	MaxSize    types.String `tfsdk:"max_size"`
+}
+
+type schedulerModel struct {
+	Selected    types.String          `tfsdk:"selected"`
+	DockerLocal *schedulerDockerModel `tfsdk:"docker_local"`
+	K3s         *schedulerK3sModel    `tfsdk:"k3s"`
+}
+
+type schedulerDockerModel struct {
+	InitProcess           types.Bool  `tfsdk:"init_process"`
+	ParallelScheduleCount types.Int64 `tfsdk:"parallel_schedule_count"`
+}
+
+type schedulerK3sModel struct {
+	Namespace         types.String `tfsdk:"namespace"`
+	DeployTimeout     types.String `tfsdk:"deploy_timeout"`
+	ImagePullSecrets  types.String `tfsdk:"image_pull_secrets"`
+	RollbackOnFailure types.Bool   `tfsdk:"rollback_on_failure"`
+}
+
+type gitModel struct {
+	DeployBranch     types.String `tfsdk:"deploy_branch"`
+	KeepGitDir       types.Bool   `tfsdk:"keep_git_dir"`
+	RevEnvVar        types.String `tfsdk:"rev_env_var"`
+	ClearSourceImage types.Bool   `tfsdk:"clear_source_image"`
+}
+
+type resourcesModel struct {
+	Limit   *resourceValuesModel `tfsdk:"limit"`
+	Reserve *resourceValuesModel `tfsdk:"reserve"`
+}
+
+type resourceValuesModel struct {
+	Cpu        types.String `tfsdk:"cpu"`
+	Memory     types.String `tfsdk:"memory"`
+	MemorySwap types.String `tfsdk:"memory_swap"`
+	NvidiaGpu  types.String `tfsdk:"nvidia_gpu"`
+}
+
 type deployModel struct {
 	Type             types.String `tfsdk:"type"`
 	Login            types.String `tfsdk:"login"`
@@ -107,12 +227,31 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 		Attributes: map[string]schema.Attribute{
 			"app_name": schema.StringAttribute{
 				Required:    true,
-				Description: "Name of application to manage",
+				Description: "Name of application to manage. App is renamed in place (apps:rename) on change",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z][a-z0-9-]*$`), "invalid app_name"),
+				},
+			},
+			"clone_from": schema.StringAttribute{
+				Optional: true,
+				Description: strings.Join([]string{
+					"Name of existing app to create app from (apps:clone). Config and other settings of source app are kept unless overridden by attributes of this resource",
+					"",
+					"Used only on create. Changing it replaces app, while setting it for imported app or removing it doesn't.",
+					"Deployed clone is restarted to apply overridden settings unless it is deployed again by deploy. Proxy state of source app is kept if ports are not set.",
+				}, "\n"),
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(requiresReplaceIfChangedString, "Changing it replaces app", "Changing it replaces app"),
 				},
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z][a-z0-9-]*$`), "invalid app_name"),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z][a-z0-9-]*$`), "invalid clone_from"),
+				},
+			},
+			"clone_skip_deploy": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Don't deploy cloned app (apps:clone --skip-deploy). Used only on create, changing it replaces app. Default: false",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplaceIf(requiresReplaceIfChangedBool, "Changing it replaces app", "Changing it replaces app"),
 				},
 			},
 			"config": schema.MapAttribute{
@@ -124,6 +263,31 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
 				},
 			},
+			"sensitive_config": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Sensitive config (env vars) for app. The same as config, but values are hidden in plan output and logs",
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`), "invalid name")),
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"config_mode": schema.StringAttribute{
+				Optional: true,
+				Description: strings.Join([]string{
+					"Config management mode. Allowed values: additive, exclusive. Default: additive",
+					"",
+					"In additive mode only vars from config are managed, other vars are ignored.",
+					"In exclusive mode vars not present in config are shown as drift and removed on apply.",
+					"Vars managed by dokku (DOKKU_*, GIT_REV, NO_VHOST) are ignored in both modes.",
+					"Vars set by service links (dokku_*_link resources), e.g. DATABASE_URL, are ignored too. They are found by the same value as DOKKU_<SERVICE>_<NAME>_URL var set by link,",
+					"so link vars changed outside of dokku are shown as drift in exclusive mode.",
+				}, "\n"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("additive", "exclusive"),
+				},
+			},
 			"storage": schema.MapNestedAttribute{
 				Optional:    true,
 				Description: "Persistent storage setup for app. Keys are storage names or absolute paths to host directories",
@@ -151,6 +315,22 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 								stringvalidator.LengthAtLeast(1),
 							},
 						},
+						"read_only": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Mount storage in read-only mode. Default: false",
+						},
+						"mount_options": schema.SetAttribute{
+							Optional:    true,
+							Description: "Additional docker volume options to mount storage with. For example: z, nocopy. Use read_only attribute instead of ro option",
+							ElementType: types.StringType,
+							Validators: []validator.Set{
+								setvalidator.ValueStringsAre(
+									stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9_=-]+$`), "invalid mount option"),
+									stringvalidator.NoneOf("ro", "rw"),
+								),
+							},
+						},
+						"files": storageFilesSchemaAttribute(),
 					},
 				},
 				Validators: []validator.Map{
@@ -162,12 +342,56 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				Description: "Checks setup for app",
 				Attributes: map[string]schema.Attribute{
 					"status": schema.StringAttribute{
-						Required:    true,
-						Description: "Checks status. Default: enabled",
+						Optional:    true,
+						Description: "Checks status for all process types. Default: enabled",
 						Validators: []validator.String{
 							stringvalidator.OneOf("enabled", "disabled", "skipped"),
 						},
 					},
+					"disabled": schema.SetAttribute{
+						Optional:    true,
+						Description: "Process types with disabled checks. Can't be used with status disabled or skipped",
+						ElementType: types.StringType,
+						Validators: []validator.Set{
+							setvalidator.ValueStringsAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9_-]+$`), "invalid process type")),
+						},
+					},
+					"skipped": schema.SetAttribute{
+						Optional:    true,
+						Description: "Process types with skipped checks. Can't be used with status disabled or skipped",
+						ElementType: types.StringType,
+						Validators: []validator.Set{
+							setvalidator.ValueStringsAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9_-]+$`), "invalid process type")),
+						},
+					},
+					"wait_to_retire": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Seconds to wait before old containers are stopped after deploy (checks:set wait-to-retire)",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"attempts": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Count of attempts of each check (DOKKU_CHECKS_ATTEMPTS config var)",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"timeout": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Timeout of each check attempt in seconds (DOKKU_CHECKS_TIMEOUT config var)",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"wait": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Seconds to wait before running checks (DOKKU_CHECKS_WAIT config var)",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
 				},
 			},
 			"ports": schema.MapNestedAttribute{
@@ -343,893 +567,2876 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 					},
 				},
 			},
-		},
-	}
-}
-func (r *appResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
-	var data appResourceModel
-	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	if data.Deploy != nil {
-		switch data.Deploy.Type.ValueString() {
-		case "archive":
-			if data.Deploy.ArchiveUrl.IsNull() {
-				resp.Diagnostics.AddAttributeError(path.Root("deploy").AtName("archive_url"), "archive_url must be set for type archive", "archive_url must be set for type archive")
-			}
-		case "docker_image":
-			if data.Deploy.DockerImage.IsNull() {
-				resp.Diagnostics.AddAttributeError(path.Root("deploy").AtName("docker_image"), "docker_image must be set for type archive", "docker_image must be set for type archive")
-			}
-		case "git_repository":
-			if data.Deploy.GitRepository.IsNull() {
-				resp.Diagnostics.AddAttributeError(path.Root("deploy").AtName("git_repository"), "git_repository must be set for type archive", "git_repository must be set for type archive")
-			}
-		default:
-			resp.Diagnostics.AddAttributeError(path.Root("deploy").AtName("type"), "Invalid type value", "Invalid type value")
-		}
-	}
-}
-
-// Read refreshes the Terraform state with the latest data.
-func (r *appResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	// Get current state
-	var state appResourceModel
-	diags := req.State.Get(ctx, &state)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	// Check app existence
-	exists, err := r.client.AppExists(ctx, state.AppName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(path.Root("app_name"), "Unable to check app existence", "Unable to check app existence. "+err.Error())
-		return
-	}
-	if !exists {
-		resp.State.RemoveResource(ctx)
-		return
-	}
-
-	config, err := r.client.ConfigExport(ctx, state.AppName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to get config", "Unable to get config. "+err.Error())
-	} else {
-		cfg := make(map[string]basetypes.StringValue)
-		for k, v := range config {
-			found := false
-			for knownK := range state.Config {
-				if k == knownK {
-					found = true
-					break
-				}
-			}
-			// only known keys
-			if found {
-				cfg[k] = basetypes.NewStringValue(v)
-			}
-		}
-		if len(cfg) == 0 {
-			state.Config = nil
-		} else {
-			state.Config = cfg
-		}
-	}
-
-	storage, err := r.client.StorageExport(ctx, state.AppName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(path.Root("storage"), "Unable to get storage", "Unable to get storage. "+err.Error())
-	} else {
-		if len(storage) == 0 {
-			state.Storage = nil
-		} else {
-			stateStorage := make(map[string]storageModel)
-			for k, v := range storage {
-				localDirectory := basetypes.NewStringNull()
-				if storageConfig, ok := state.Storage[k]; ok {
-					localDirectory = storageConfig.LocalDirectory
-				}
-
-				stateStorage[k] = storageModel{
-					MountPath:      basetypes.NewStringValue(v),
-					LocalDirectory: localDirectory,
-				}
-			}
-			state.Storage = stateStorage
-		}
-	}
-
-	checkStatus, err := r.client.ChecksGet(ctx, state.AppName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(path.Root("checks"), "Unable to get checks", "Unable to get checks. "+err.Error())
-	} else {
-		if checkStatus == "enabled" {
-			state.Checks = nil
-		} else {
-			state.Checks = &checkModel{
-				Status: basetypes.NewStringValue(checkStatus),
-			}
-		}
-	}
-
-	domains, err := r.client.DomainsExport(ctx, state.AppName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to get domains", "Unable to get domains. "+err.Error())
-	} else {
-		if len(domains) == 0 {
-			state.Domains = nil
-		} else {
-			state.Domains = make([]types.String, len(domains))
-			for i, d := range domains {
-				state.Domains[i] = basetypes.NewStringValue(d)
-			}
-		}
-	}
-
-	ports, err := r.client.PortsExport(ctx, state.AppName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to get ports", "Unable to get ports. "+err.Error())
-	} else {
-		pp := make(map[string]portModel)
-		for _, p := range ports {
-			found := false
-			for v := range state.Ports {
-				if v == p.HostPort {
-					found = true
-					break
-				}
-			}
-			for v := range state.ProxyPorts {
-				if v == p.HostPort {
-					found = true
-					break
-				}
-			}
-			// only known hostport's
-			if found {
-				pp[p.HostPort] = portModel{
-					Scheme:        basetypes.NewStringValue(p.Scheme),
-					ContainerPort: basetypes.NewStringValue(p.ContainerPort),
-				}
-			}
-		}
-		if len(pp) == 0 {
-			state.Ports = nil
-			state.ProxyPorts = nil
-		} else {
-			state.Ports = pp
-			// Don't set state.ProxyPorts and don't check it later - use only state.Ports
-			// state.ProxyPorts = pp
-			state.ProxyPorts = nil
-		}
-	}
-
-	// dockerOptions -- unable to read because it can be set externally
-
-	networks, err := r.client.NetworksReport(ctx, state.AppName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(path.Root("networks"), "Unable to get networks", "Unable to get networks. "+err.Error())
-	} else {
-		var attachPostCreate types.String
-		if networks["attach post create"] != "" {
-			attachPostCreate = basetypes.NewStringValue(networks["attach post create"])
-		} else {
-			attachPostCreate = basetypes.NewStringNull()
-		}
-		var attachPostDeploy types.String
-		if networks["attach post deploy"] != "" {
-			attachPostDeploy = basetypes.NewStringValue(networks["attach post deploy"])
-		} else {
-			attachPostDeploy = basetypes.NewStringNull()
-		}
-		var initialNetwork types.String
-		if networks["initial network"] != "" {
-			initialNetwork = basetypes.NewStringValue(networks["initial network"])
-		} else {
-			initialNetwork = basetypes.NewStringNull()
-		}
-		if attachPostCreate.IsNull() && attachPostDeploy.IsNull() && initialNetwork.IsNull() {
-			state.Networks = nil
-		} else {
-			state.Networks = &networkModel{
-				AttachPostCreate: attachPostCreate,
-				AttachPostDeploy: attachPostDeploy,
-				InitialNetwork:   initialNetwork,
-			}
-		}
-	}
-
-	// deploy -- unable to read
+			"scale": schema.MapAttribute{
+				Optional: true,
+				Description: strings.Join([]string{
+					"Count of containers for each process type (ps:scale)",
+					"",
+					"Process types removed from this map are not scaled down.",
+				}, "\n"),
+				ElementType: types.Int64Type,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9_-]+$`), "invalid process type")),
+					mapvalidator.ValueInt64sAre(int64validator.AtLeast(0)),
+				},
+			},
+			"resources": schema.MapNestedAttribute{
+				Optional: true,
+				Description: strings.Join([]string{
+					"Resource limits and reservations for app. Keys are process types or `default` for all process types",
+					"",
+					"Process types removed from this map keep their limits and reservations.",
+				}, "\n"),
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"limit":   resourceValuesSchemaAttribute("Resource limits (resource:limit)"),
+						"reserve": resourceValuesSchemaAttribute("Resource reservations (resource:reserve)"),
+					},
+				},
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9_-]+$`), "invalid process type")),
+				},
+			},
+			"build": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Build setup for app. Changes are applied on next deploy",
+				Attributes: map[string]schema.Attribute{
+					"builder": schema.StringAttribute{
+						Optional:    true,
+						Description: "Builder to use (builder:set selected). Allowed values: dockerfile, herokuish, lambda, nixpacks, null, pack. Default: detected by dokku",
+						Validators: []validator.String{
+							stringvalidator.OneOf("dockerfile", "herokuish", "lambda", "nixpacks", "null", "pack"),
+						},
+					},
+					"build_dir": schema.StringAttribute{
+						Optional:    true,
+						Description: "Subdirectory of repository to build app from (builder:set build-dir)",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"dockerfile_path": schema.StringAttribute{
+						Optional:    true,
+						Description: "Path to Dockerfile for dockerfile builder (builder-dockerfile:set dockerfile-path)",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"projecttoml_path": schema.StringAttribute{
+						Optional:    true,
+						Description: "Path to project.toml for pack builder (builder-pack:set projecttoml-path)",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"nixpackstoml_path": schema.StringAttribute{
+						Optional:    true,
+						Description: "Path to nixpacks.toml for nixpacks builder (builder-nixpacks:set nixpackstoml-path)",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"nixpacks_no_cache": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Disable build cache for nixpacks builder (builder-nixpacks:set no-cache)",
+					},
+				},
+			},
+			"buildpacks": schema.ListAttribute{
+				Optional:    true,
+				Description: "Ordered list of buildpacks for herokuish and pack builders. Changes are applied on next deploy. Buildpacks are not managed if omitted",
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"nginx": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Nginx proxy setup for app (nginx:set). Omitted properties are not managed",
+				Attributes: map[string]schema.Attribute{
+					"client_max_body_size": schema.StringAttribute{
+						Optional:    true,
+						Description: "Max size of request body, e.g. 50m. Use 0 to disable checking",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"proxy_connect_timeout": schema.StringAttribute{
+						Optional:    true,
+						Description: "Timeout for establishing connection with app, e.g. 60s",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"proxy_read_timeout": schema.StringAttribute{
+						Optional:    true,
+						Description: "Timeout for reading response from app, e.g. 60s",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"proxy_send_timeout": schema.StringAttribute{
+						Optional:    true,
+						Description: "Timeout for sending request to app, e.g. 60s",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"hsts": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Enable HSTS header for HTTPS requests",
+					},
+					"hsts_include_subdomains": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Add includeSubdomains to HSTS header",
+					},
+					"hsts_max_age": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Max age of HSTS header in seconds",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"hsts_preload": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Add preload to HSTS header",
+					},
+					"access_log_format": schema.StringAttribute{
+						Optional:    true,
+						Description: "Name of custom log format for access log",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"access_log_path": schema.StringAttribute{
+						Optional:    true,
+						Description: "Path to access log. Use off to disable logging",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"error_log_path": schema.StringAttribute{
+						Optional:    true,
+						Description: "Path to error log. Use off to disable logging",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"bind_address_ipv4": schema.StringAttribute{
+						Optional:    true,
+						Description: "IPv4 address to bind to",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"bind_address_ipv6": schema.StringAttribute{
+						Optional:    true,
+						Description: "IPv6 address to bind to",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"x_forwarded_for_value": schema.StringAttribute{
+						Optional:    true,
+						Description: "Value of X-Forwarded-For header passed to app",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"x_forwarded_port_value": schema.StringAttribute{
+						Optional:    true,
+						Description: "Value of X-Forwarded-Port header passed to app",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"x_forwarded_proto_value": schema.StringAttribute{
+						Optional:    true,
+						Description: "Value of X-Forwarded-Proto header passed to app",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"x_forwarded_ssl": schema.StringAttribute{
+						Optional:    true,
+						Description: "Value of X-Forwarded-Ssl header passed to app",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+				},
+			},
+			"proxy": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Proxy setup for app",
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Optional:    true,
+						Description: "Proxy implementation for app (proxy:set). Allowed values: caddy, haproxy, nginx, null, openresty, traefik. Default: global proxy type",
+						Validators: []validator.String{
+							stringvalidator.OneOf("caddy", "haproxy", "nginx", "null", "openresty", "traefik"),
+						},
+					},
+					"enabled": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Enable or disable proxy for app. Default: enabled if ports are set",
+					},
+					"labels": schema.MapAttribute{
+						Optional:    true,
+						Description: "Container labels for caddy, haproxy and traefik proxies (<type>:labels:add). Keys are label names. Values can't contain whitespace, e.g. traefik rules must be written as Host(`a.com`)||Host(`b.com`)",
+						ElementType: types.StringType,
+						Validators: []validator.Map{
+							mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9._/-]+$`), "invalid label name")),
+							mapvalidator.ValueStringsAre(stringvalidator.RegexMatches(regexp.MustCompile(`^\S+$`), "must not be empty or contain whitespace")),
+						},
+					},
+					"properties": schema.MapAttribute{
+						Optional:    true,
+						Description: "App properties of caddy, haproxy and traefik proxies (<type>:set). Keys are property names",
+						ElementType: types.StringType,
+						Validators: []validator.Map{
+							mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9-]+$`), "invalid property name")),
+							mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+						},
+					},
+				},
+			},
+			"process": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Process setup for app (ps:set). Omitted properties are not managed",
+				Attributes: map[string]schema.Attribute{
+					"restart_policy": schema.StringAttribute{
+						Optional:    true,
+						Description: "Restart policy of containers: always, no, unless-stopped, on-failure or on-failure:<max retries>. Default: on-failure:10",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^(always|no|unless-stopped|on-failure(:[0-9]+)?)$`), "invalid restart policy"),
+						},
+					},
+					"stop_timeout_seconds": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Seconds to wait for containers to stop before killing them",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"procfile_path": schema.StringAttribute{
+						Optional:    true,
+						Description: "Path to Procfile in repository. Changes are applied on next deploy",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+				},
+			},
+			"logs": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Logs setup for app (logs:set). Omitted properties are not managed",
+				Attributes: map[string]schema.Attribute{
+					"vector_sink": schema.StringAttribute{
+						Optional:    true,
+						Description: "Vector sink for app logs in DSN format, e.g. loki://?endpoint=http://loki:3100&encoding[codec]=json&labels[app]=my-app",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9_]+://\S*$`), "must be in DSN format without spaces"),
+						},
+					},
+					"max_size": schema.StringAttribute{
+						Optional:    true,
+						Description: "Max size of container log file, e.g. 10m, or unlimited. Applied to new containers",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^([0-9]+[kmg]?|unlimited)$`), "must be size with optional k, m or g unit, or unlimited"),
+						},
+					},
+				},
+			},
+			"scheduler": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Scheduler setup for app (scheduler:set, scheduler-docker-local:set, scheduler-k3s:set). Omitted properties are not managed. Changes are applied on next deploy",
+				Attributes: map[string]schema.Attribute{
+					"selected": schema.StringAttribute{
+						Optional:    true,
+						Description: "Scheduler of app. Allowed values: docker-local, k3s, null. Default: global scheduler",
+						Validators: []validator.String{
+							stringvalidator.OneOf("docker-local", "k3s", "null"),
+						},
+					},
+					"docker_local": schema.SingleNestedAttribute{
+						Optional:    true,
+						Description: "Properties of docker-local scheduler",
+						Attributes: map[string]schema.Attribute{
+							"init_process": schema.BoolAttribute{
+								Optional:    true,
+								Description: "Run init process in containers",
+							},
+							"parallel_schedule_count": schema.Int64Attribute{
+								Optional:    true,
+								Description: "Number of containers deployed in parallel",
+								Validators: []validator.Int64{
+									int64validator.AtLeast(1),
+								},
+							},
+						},
+					},
+					"k3s": schema.SingleNestedAttribute{
+						Optional:    true,
+						Description: "Properties of k3s scheduler",
+						Attributes: map[string]schema.Attribute{
+							"namespace": schema.StringAttribute{
+								Optional:    true,
+								Description: "Kubernetes namespace for app resources",
+								Validators: []validator.String{
+									stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`), "invalid namespace"),
+								},
+							},
+							"deploy_timeout": schema.StringAttribute{
+								Optional:    true,
+								Description: "Timeout of rollout, e.g. 300s",
+								Validators: []validator.String{
+									stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9]+[smh]?$`), "must be duration with optional s, m or h unit"),
+								},
+							},
+							"image_pull_secrets": schema.StringAttribute{
+								Optional:    true,
+								Description: "Name of kubernetes secret used to pull app image",
+								Validators: []validator.String{
+									stringvalidator.LengthAtLeast(1),
+								},
+							},
+							"rollback_on_failure": schema.BoolAttribute{
+								Optional:    true,
+								Description: "Roll back to previous release if deploy fails",
+							},
+						},
+					},
+				},
+			},
+			"git": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Git setup for app (git:set), used by git push and git_repository deploys. Omitted properties are not managed",
+				Attributes: map[string]schema.Attribute{
+					"deploy_branch": schema.StringAttribute{
+						Optional:    true,
+						Description: "Branch deployed on git push. Default: global deploy branch or master",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"keep_git_dir": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Keep .git directory in build context. Changes are applied on next deploy",
+					},
+					"rev_env_var": schema.StringAttribute{
+						Optional:    true,
+						Description: "Config variable set to deployed git revision. Previous variable is unset on change, GIT_REV is never treated as app config. To disable setting revision, run git:set <app> rev-env-var \"\" on server and omit this attribute. Default: GIT_REV",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`), "invalid variable name"),
+						},
+					},
+					"clear_source_image": schema.BoolAttribute{
+						Optional: true,
+						Description: strings.Join([]string{
+							"Unset source image left by previous docker image deploy, so git deploys build app from repository",
+							"",
+							"Ignored if deploy type is docker_image.",
+						}, "\n"),
+					},
+				},
+			},
+			"running": schema.BoolAttribute{
+				Optional: true,
+				Description: strings.Join([]string{
+					"Desired state of deployed app: true to start it (ps:start), false to stop it (ps:stop). Default: not managed",
+					"",
+					"Stopped app is not started by changes which require restart. Running state of app which wasn't deployed yet is not checked.",
+				}, "\n"),
+			},
+			"lock_during_apply": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Lock app (apps:lock) while its settings are changed on create or update, including rename, to prevent concurrent deploys, e.g. by git push. App is unlocked before deploy, scale, restart and proxy rebuild done by this resource, because dokku refuses to deploy locked app. Apply fails if app is already locked. Default: false",
+			},
+			"deployed_image": schema.StringAttribute{
+				Computed:    true,
+				Description: "Docker image app is deployed from (source image from git:report). Empty if app is not deployed from docker image",
+			},
+			"deployed_revision": schema.StringAttribute{
+				Computed:    true,
+				Description: "Last deployed git revision (sha from git:report). Empty if app is not deployed",
+			},
+		},
+	}
+}
+func (r *appResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data appResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Checks != nil {
+		status := data.Checks.Status.ValueString()
+		if (status == "disabled" || status == "skipped") && (len(data.Checks.Disabled) != 0 || len(data.Checks.Skipped) != 0) {
+			resp.Diagnostics.AddAttributeError(path.Root("checks").AtName("status"), "Process types can't be set when checks are disabled or skipped for all", "Process types can't be set when checks are disabled or skipped for all")
+		}
+		for _, disabled := range data.Checks.Disabled {
+			for _, skipped := range data.Checks.Skipped {
+				if !disabled.IsUnknown() && disabled.Equal(skipped) {
+					resp.Diagnostics.AddAttributeError(path.Root("checks").AtName("skipped"), "Process type can't be disabled and skipped at the same time", "Process type can't be disabled and skipped at the same time: "+disabled.ValueString())
+				}
+			}
+		}
+	}
+
+	if data.Proxy != nil && (len(data.Proxy.Labels) != 0 || len(data.Proxy.Properties) != 0) {
+		switch data.Proxy.Type.ValueString() {
+		case "caddy", "haproxy", "traefik":
+		default:
+			if !data.Proxy.Type.IsUnknown() {
+				resp.Diagnostics.AddAttributeError(path.Root("proxy").AtName("type"), "type must be caddy, haproxy or traefik to set labels or properties", "type must be caddy, haproxy or traefik to set labels or properties")
+			}
+		}
+	}
+
+	for k := range data.SensitiveConfig {
+		if _, ok := data.Config[k]; ok {
+			resp.Diagnostics.AddAttributeError(path.Root("sensitive_config").AtMapKey(k), "Config var is already set in config", "Config var is already set in config")
+		}
+	}
+
+	for storageName, storage := range data.Storage {
+		validateStorageFiles(storage.Files, path.Root("storage").AtMapKey(storageName).AtName("files"), &resp.Diagnostics)
+	}
+
+	if data.Deploy != nil {
+		switch data.Deploy.Type.ValueString() {
+		case "archive":
+			if data.Deploy.ArchiveUrl.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root("deploy").AtName("archive_url"), "archive_url must be set for type archive", "archive_url must be set for type archive")
+			}
+		case "docker_image":
+			if data.Deploy.DockerImage.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root("deploy").AtName("docker_image"), "docker_image must be set for type archive", "docker_image must be set for type archive")
+			}
+		case "git_repository":
+			if data.Deploy.GitRepository.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root("deploy").AtName("git_repository"), "git_repository must be set for type archive", "git_repository must be set for type archive")
+			}
+		default:
+			resp.Diagnostics.AddAttributeError(path.Root("deploy").AtName("type"), "Invalid type value", "Invalid type value")
+		}
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *appResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state appResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Check app existence
+	exists, err := r.client.AppExists(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("app_name"), "Unable to check app existence", "Unable to check app existence. "+err.Error())
+		return
+	}
+	if !exists {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	config, err := r.client.ConfigExport(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to get config", "Unable to get config. "+err.Error())
+	} else {
+		exclusive := state.ConfigMode.ValueString() == "exclusive"
+		cfg := make(map[string]basetypes.StringValue)
+		sensitiveCfg := make(map[string]basetypes.StringValue)
+		for k, v := range config {
+			if _, ok := state.SensitiveConfig[k]; ok {
+				sensitiveCfg[k] = basetypes.NewStringValue(v)
+				continue
+			}
+			found := false
+			for knownK := range state.Config {
+				if k == knownK {
+					found = true
+					break
+				}
+			}
+			// only known keys, or all keys not managed by dokku in exclusive mode
+			if found || (exclusive && !isDokkuManagedConfigKey(k, config, state.Git)) {
+				cfg[k] = basetypes.NewStringValue(v)
+			}
+		}
+		if len(cfg) == 0 {
+			state.Config = nil
+		} else {
+			state.Config = cfg
+		}
+		if len(sensitiveCfg) == 0 {
+			state.SensitiveConfig = nil
+		} else {
+			state.SensitiveConfig = sensitiveCfg
+		}
+	}
+
+	storage, err := r.client.StorageExport(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("storage"), "Unable to get storage", "Unable to get storage. "+err.Error())
+	} else {
+		if len(storage) == 0 {
+			state.Storage = nil
+		} else {
+			stateStorage := make(map[string]storageModel)
+			for k, v := range storage {
+				localDirectory := basetypes.NewStringNull()
+				readOnly := basetypes.NewBoolNull()
+				mountOptions := basetypes.NewSetNull(types.StringType)
+				var files map[string]storageFileModel
+				if storageConfig, ok := state.Storage[k]; ok {
+					localDirectory = storageConfig.LocalDirectory
+					readOnly = storageConfig.ReadOnly
+					mountOptions = storageConfig.MountOptions
+					files = storageConfig.Files
+				}
+
+				isReadOnly := false
+				var extraOptions []attr.Value
+				for _, option := range v.Options {
+					switch option {
+					case "ro":
+						isReadOnly = true
+					case "rw":
+					default:
+						extraOptions = append(extraOptions, basetypes.NewStringValue(option))
+					}
+				}
+				if isReadOnly || !readOnly.IsNull() {
+					readOnly = basetypes.NewBoolValue(isReadOnly)
+				}
+				if len(extraOptions) != 0 || !mountOptions.IsNull() {
+					mountOptions = basetypes.NewSetValueMust(types.StringType, extraOptions)
+				}
+
+				if len(files) != 0 {
+					files, err = readStorageFiles(ctx, r.client, k, files)
+					if err != nil {
+						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(k).AtName("files"), "Unable to get storage files", "Unable to get storage files. "+err.Error())
+					}
+				}
+
+				stateStorage[k] = storageModel{
+					MountPath:      basetypes.NewStringValue(v.MountPath),
+					ReadOnly:       readOnly,
+					MountOptions:   mountOptions,
+					LocalDirectory: localDirectory,
+					Files:          files,
+				}
+			}
+			state.Storage = stateStorage
+		}
+	}
+
+	checks, err := r.readChecks(ctx, state.AppName.ValueString(), state.Checks, config)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("checks"), "Unable to get checks", "Unable to get checks. "+err.Error())
+	} else {
+		state.Checks = checks
+	}
+
+	domains, err := r.client.DomainsExport(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to get domains", "Unable to get domains. "+err.Error())
+	} else {
+		if len(domains) == 0 {
+			state.Domains = nil
+		} else {
+			state.Domains = make([]types.String, len(domains))
+			for i, d := range domains {
+				state.Domains[i] = basetypes.NewStringValue(d)
+			}
+		}
+	}
+
+	ports, err := r.client.PortsExport(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to get ports", "Unable to get ports. "+err.Error())
+	} else {
+		pp := make(map[string]portModel)
+		for _, p := range ports {
+			found := false
+			for v := range state.Ports {
+				if v == p.HostPort {
+					found = true
+					break
+				}
+			}
+			for v := range state.ProxyPorts {
+				if v == p.HostPort {
+					found = true
+					break
+				}
+			}
+			// only known hostport's
+			if found {
+				pp[p.HostPort] = portModel{
+					Scheme:        basetypes.NewStringValue(p.Scheme),
+					ContainerPort: basetypes.NewStringValue(p.ContainerPort),
+				}
+			}
+		}
+		if len(pp) == 0 {
+			state.Ports = nil
+			state.ProxyPorts = nil
+		} else {
+			state.Ports = pp
+			// Don't set state.ProxyPorts and don't check it later - use only state.Ports
+			// state.ProxyPorts = pp
+			state.ProxyPorts = nil
+		}
+	}
+
+	dockerOptions, err := r.client.DockerOptionsReport(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("docker_options"), "Unable to get docker options", "Unable to get docker options. "+err.Error())
+	} else {
+		// only known options, because other options can be set externally (for example by storage:mount)
+		opts := make(map[string]dockerOptionModel)
+		for option := range state.DockerOptions {
+			normalizedOption := dokkuclient.NormalizeDockerOption(option)
+			var phases []attr.Value
+			for _, phase := range dokkuclient.DockerOptionsPhases {
+				for _, existingOption := range dockerOptions[phase] {
+					if existingOption == normalizedOption {
+						phases = append(phases, basetypes.NewStringValue(phase))
+						break
+					}
+				}
+			}
+			if len(phases) != 0 {
+				opts[option] = dockerOptionModel{
+					Phase: basetypes.NewSetValueMust(types.StringType, phases),
+				}
+			}
+		}
+		if len(opts) == 0 {
+			state.DockerOptions = nil
+		} else {
+			state.DockerOptions = opts
+		}
+	}
+
+	build, err := r.readBuild(ctx, state.AppName.ValueString(), state.Build)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("build"), "Unable to get build setup", "Unable to get build setup. "+err.Error())
+	} else {
+		state.Build = build
+	}
+
+	// buildpacks set outside of terraform are not managed
+	if state.Buildpacks != nil {
+		buildpacks, err := r.client.BuildpacksList(ctx, state.AppName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("buildpacks"), "Unable to get buildpacks", "Unable to get buildpacks. "+err.Error())
+		} else {
+			if len(buildpacks) == 0 {
+				state.Buildpacks = nil
+			} else {
+				state.Buildpacks = make([]types.String, len(buildpacks))
+				for i, b := range buildpacks {
+					state.Buildpacks[i] = basetypes.NewStringValue(b)
+				}
+			}
+		}
+	}
+
+	nginx, err := r.readNginx(ctx, state.AppName.ValueString(), state.Nginx)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("nginx"), "Unable to get nginx setup", "Unable to get nginx setup. "+err.Error())
+	} else {
+		state.Nginx = nginx
+	}
+
+	if state.Proxy != nil {
+		proxy, err := r.readProxy(ctx, state.AppName.ValueString(), *state.Proxy)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("proxy"), "Unable to get proxy setup", "Unable to get proxy setup. "+err.Error())
+		} else {
+			state.Proxy = proxy
+		}
+	}
+
+	process, err := r.readProcess(ctx, state.AppName.ValueString(), state.Process)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("process"), "Unable to get process setup", "Unable to get process setup. "+err.Error())
+	} else {
+		state.Process = process
+	}
+
+	logs, err := r.readLogs(ctx, state.AppName.ValueString(), state.Logs)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("logs"), "Unable to get logs setup", "Unable to get logs setup. "+err.Error())
+	} else {
+		state.Logs = logs
+	}
+
+	scheduler, err := r.readScheduler(ctx, state.AppName.ValueString(), state.Scheduler)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("scheduler"), "Unable to get scheduler setup", "Unable to get scheduler setup. "+err.Error())
+	} else {
+		state.Scheduler = scheduler
+	}
+
+	git, err := r.readGit(ctx, state.AppName.ValueString(), state.Git, state.Deploy)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("git"), "Unable to get git setup", "Unable to get git setup. "+err.Error())
+	} else {
+		state.Git = git
+	}
+
+	networks, err := r.client.NetworksReport(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("networks"), "Unable to get networks", "Unable to get networks. "+err.Error())
+	} else {
+		var attachPostCreate types.String
+		if networks["attach post create"] != "" {
+			attachPostCreate = basetypes.NewStringValue(networks["attach post create"])
+		} else {
+			attachPostCreate = basetypes.NewStringNull()
+		}
+		var attachPostDeploy types.String
+		if networks["attach post deploy"] != "" {
+			attachPostDeploy = basetypes.NewStringValue(networks["attach post deploy"])
+		} else {
+			attachPostDeploy = basetypes.NewStringNull()
+		}
+		var initialNetwork types.String
+		if networks["initial network"] != "" {
+			initialNetwork = basetypes.NewStringValue(networks["initial network"])
+		} else {
+			initialNetwork = basetypes.NewStringNull()
+		}
+		if attachPostCreate.IsNull() && attachPostDeploy.IsNull() && initialNetwork.IsNull() {
+			state.Networks = nil
+		} else {
+			state.Networks = &networkModel{
+				AttachPostCreate: attachPostCreate,
+				AttachPostDeploy: attachPostDeploy,
+				InitialNetwork:   initialNetwork,
+			}
+		}
+	}
+
+	scale, err := r.client.ProcessScaleReport(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("scale"), "Unable to get scale", "Unable to get scale. "+err.Error())
+	} else {
+		sc := make(map[string]types.Int64)
+		for processType := range state.Scale {
+			// only known process types
+			if count, ok := scale[processType]; ok {
+				sc[processType] = basetypes.NewInt64Value(count)
+			}
+		}
+		if len(sc) == 0 {
+			state.Scale = nil
+		} else {
+			state.Scale = sc
+		}
+	}
+
+	resources, err := r.client.ResourceReport(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("resources"), "Unable to get resources", "Unable to get resources. "+err.Error())
+	} else {
+		res := make(map[string]resourcesModel)
+		for processType := range state.Resources {
+			// only known process types
+			limit := parseResourceValues(resources[processType]["limit"])
+			reserve := parseResourceValues(resources[processType]["reserve"])
+			if limit != nil || reserve != nil {
+				res[processType] = resourcesModel{
+					Limit:   limit,
+					Reserve: reserve,
+				}
+			}
+		}
+		if len(res) == 0 {
+			state.Resources = nil
+		} else {
+			state.Resources = res
+		}
+	}
+
+	if !state.Running.IsNull() {
+		running, deployed, err := r.isRunning(ctx, state.AppName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("running"), "Unable to get running state", "Unable to get running state. "+err.Error())
+		} else if deployed {
+			// app which wasn't deployed yet matches any desired state
+			state.Running = basetypes.NewBoolValue(running)
+		}
+	}
+
+	deployed, deployedImage, deployedRevision, err := r.readDeployment(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deploy"), "Unable to get deployment", "Unable to get deployment. "+err.Error())
+	} else {
+		state.DeployedImage = deployedImage
+		state.DeployedRevision = deployedRevision
+
+		// show diff if deployed source differs from configured one
+		if state.Deploy != nil {
+			if !deployed {
+				state.Deploy = nil
+			} else {
+				switch state.Deploy.Type.ValueString() {
+				case "docker_image":
+					state.Deploy.DockerImage = deployedImage
+				case "git_repository":
+					// only sha can be compared with deployed revision without fetching repository
+					ref := state.Deploy.GitRepositoryRef.ValueString()
+					if isGitSha(ref) && !strings.HasPrefix(deployedRevision.ValueString(), ref) {
+						state.Deploy.GitRepositoryRef = deployedRevision
+					}
+				}
+			}
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *appResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan appResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Check app existence
+	exists, err := r.client.AppExists(ctx, plan.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("app_name"), "Unable to check app existence", "Unable to check app existence. "+err.Error())
+		return
+	}
+	if exists {
+		resp.Diagnostics.AddAttributeError(path.Root("app_name"), "App already exists", "App with specified name already exists")
+		return
+	}
+
+	// Create new app
+	if !plan.CloneFrom.IsNull() {
+		err = r.client.AppClone(ctx, plan.CloneFrom.ValueString(), plan.AppName.ValueString(), plan.CloneSkipDeploy.ValueBool())
+	} else {
+		err = r.client.AppCreate(ctx, plan.AppName.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create app", "Unable to create app. "+err.Error())
+		// if not created - return to not try to destroy on other errors
+		return
+	}
+
+	unlock := func() {}
+	if plan.LockDuringApply.ValueBool() {
+		lockedName := plan.AppName.ValueString()
+		unlock = r.lockApp(ctx, &lockedName, &resp.Diagnostics)
+		defer unlock()
+		if resp.Diagnostics.HasError() {
+			err := r.client.AppDestroy(ctx, plan.AppName.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Unable to destroy app", "Unable to destroy app. "+err.Error())
+			}
+			return
+		}
+	}
+
+	// config of source app is not managed in exclusive mode
+	if !plan.CloneFrom.IsNull() && plan.ConfigMode.ValueString() == "exclusive" {
+		config, err := r.client.ConfigExport(ctx, plan.AppName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to get config", "Unable to get config. "+err.Error())
+		}
+		var namesToUnset []string
+		for name := range config {
+			_, inPlan := plan.Config[name]
+			_, inPlanSensitive := plan.SensitiveConfig[name]
+			if !inPlan && !inPlanSensitive && !isDokkuManagedConfigKey(name, config, plan.Git) {
+				namesToUnset = append(namesToUnset, name)
+			}
+		}
+		if len(namesToUnset) != 0 {
+			err = r.client.ConfigUnset(ctx, plan.AppName.ValueString(), namesToUnset)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to unset config", "Unable to unset config. "+err.Error())
+			}
+		}
+	}
+
+	if len(plan.Config) != 0 || len(plan.SensitiveConfig) != 0 {
+		config := make(map[string]string)
+		for k, v := range plan.Config {
+			config[k] = v.ValueString()
+		}
+		sensitiveConfig := make(map[string]string)
+		for k, v := range plan.SensitiveConfig {
+			sensitiveConfig[k] = v.ValueString()
+		}
+		err := r.client.ConfigSet(ctx, plan.AppName.ValueString(), config, sensitiveConfig)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to set config", "Unable to set config. "+err.Error())
+		}
+	}
+
+	for hostPath, storage := range plan.Storage {
+		err := r.client.StorageEnsure(ctx, hostPath, "", storage.LocalDirectory.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(hostPath), "Unable to ensure storage", "Unable to ensure storage. "+err.Error())
+		}
+
+		_, err = syncStorageFiles(ctx, r.client, hostPath, storage.Files, nil)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(hostPath).AtName("files"), "Unable to upload storage files", "Unable to upload storage files. "+err.Error())
+		}
+
+		err = r.client.StorageMount(ctx, plan.AppName.ValueString(), hostPath, storage.MountPath.ValueString(), formatStorageMountOptions(storage))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(hostPath), "Unable to mount storage", "Unable to mount storage. "+err.Error())
+		}
+	}
+
+	r.setChecks(ctx, plan.AppName.ValueString(), plan.Checks, nil, &resp.Diagnostics)
+
+	if len(plan.Ports) != 0 || len(plan.ProxyPorts) != 0 {
+		if len(plan.ProxyPorts) > 0 {
+			resp.Diagnostics.AddAttributeWarning(path.Root("proxy_ports"), "proxy_ports attribute is deprecated, use porst attribute instead", "proxy_ports attribute is deprecated, use porst attribute instead")
+		}
+
+		var ports []dokkuclient.Port
+		for hostPort, port := range plan.Ports {
+			ports = append(ports, dokkuclient.Port{
+				Scheme:        port.Scheme.ValueString(),
+				HostPort:      hostPort,
+				ContainerPort: port.ContainerPort.ValueString(),
+			})
+		}
+		for hostPort, port := range plan.ProxyPorts {
+			ports = append(ports, dokkuclient.Port{
+				Scheme:        port.Scheme.ValueString(),
+				HostPort:      hostPort,
+				ContainerPort: port.ContainerPort.ValueString(),
+			})
+		}
+		err := r.client.PortsSet(ctx, plan.AppName.ValueString(), ports)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to set ports", "Unable to set ports. "+err.Error())
+		}
+		err = r.client.ProxyEnable(ctx, plan.AppName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to enable ports", "Unable to enable ports. "+err.Error())
+		}
+	} else if plan.CloneFrom.IsNull() {
+		// clone keeps proxy state of source app
+		err = r.client.ProxyDisable(ctx, plan.AppName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to disable ports", "Unable to disable ports. "+err.Error())
+		}
+	}
+
+	if plan.Proxy != nil {
+		r.setProxy(ctx, plan.AppName.ValueString(), *plan.Proxy, proxyModel{}, &resp.Diagnostics)
+	}
+
+	if len(plan.Domains) != 0 {
+		var domains []string
+		for _, domain := range plan.Domains {
+			domains = append(domains, domain.ValueString())
+		}
+		err := r.client.DomainsSet(ctx, plan.AppName.ValueString(), domains)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to add domain", "Unable to add domain. "+err.Error())
+		}
+		err = r.client.DomainsEnable(ctx, plan.AppName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to enable domains support", "Unable to enable domains support. "+err.Error())
+		}
+	} else {
+		err = r.client.DomainsDisable(ctx, plan.AppName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to disable domains support", "Unable to disable domains support. "+err.Error())
+		}
+	}
+
+	for option, dockerOption := range plan.DockerOptions {
+		err := r.client.DockerOptionAdd(ctx, plan.AppName.ValueString(), formatDockerOptionsPhases(dockerOption.Phase), option)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("docker_options").AtMapKey(option), "Unable to add docker option", "Unable to add docker option. "+err.Error())
+		}
+	}
+
+	if plan.Networks != nil {
+		if !plan.Networks.AttachPostCreate.IsNull() {
+			err := r.client.NetworkEnsureAndSetForApp(ctx, plan.AppName.ValueString(), "attach-post-create", plan.Networks.AttachPostCreate.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_create"), "Unable to set network", "Unable to set network. "+err.Error())
+			}
+		}
+		if !plan.Networks.AttachPostDeploy.IsNull() {
+			err := r.client.NetworkEnsureAndSetForApp(ctx, plan.AppName.ValueString(), "attach-post-deploy", plan.Networks.AttachPostDeploy.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_deploy"), "Unable to set network", "Unable to set network. "+err.Error())
+			}
+		}
+		if !plan.Networks.InitialNetwork.IsNull() {
+			err := r.client.NetworkEnsureAndSetForApp(ctx, plan.AppName.ValueString(), "initial_network", plan.Networks.InitialNetwork.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("initial_network"), "Unable to set network", "Unable to set network. "+err.Error())
+			}
+		}
+	}
+
+	r.setAppProperties(ctx, plan.AppName.ValueString(), path.Root("build"), buildProperties(plan.Build), nil, &resp.Diagnostics)
+
+	r.setAppProperties(ctx, plan.AppName.ValueString(), path.Root("nginx"), nginxProperties(plan.Nginx), nil, &resp.Diagnostics)
+	r.setAppProperties(ctx, plan.AppName.ValueString(), path.Root("process"), processProperties(plan.Process), nil, &resp.Diagnostics)
+	r.setAppProperties(ctx, plan.AppName.ValueString(), path.Root("logs"), logsProperties(plan.Logs), nil, &resp.Diagnostics)
+	r.setScheduler(ctx, plan.AppName.ValueString(), plan.Scheduler, nil, &resp.Diagnostics)
+	r.setAppProperties(ctx, plan.AppName.ValueString(), path.Root("git"), gitProperties(plan.Git), nil, &resp.Diagnostics)
+	r.clearSourceImage(ctx, plan.AppName.ValueString(), plan.Git, plan.Deploy, &resp.Diagnostics)
+
+	if len(plan.Buildpacks) != 0 {
+		err := r.client.BuildpacksSet(ctx, plan.AppName.ValueString(), formatStrings(plan.Buildpacks))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("buildpacks"), "Unable to set buildpacks", "Unable to set buildpacks. "+err.Error())
+		}
+	}
+
+	for processType, resources := range plan.Resources {
+		if resources.Limit != nil {
+			err := r.client.ResourceSet(ctx, plan.AppName.ValueString(), "limit", processType, formatResourceValues(resources.Limit))
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("resources").AtMapKey(processType).AtName("limit"), "Unable to set resource limits", "Unable to set resource limits. "+err.Error())
+			}
+		}
+		if resources.Reserve != nil {
+			err := r.client.ResourceSet(ctx, plan.AppName.ValueString(), "reserve", processType, formatResourceValues(resources.Reserve))
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("resources").AtMapKey(processType).AtName("reserve"), "Unable to set resource reservations", "Unable to set resource reservations. "+err.Error())
+			}
+		}
+	}
+
+	if len(plan.Scale) != 0 {
+		err := r.client.ProcessScale(ctx, plan.AppName.ValueString(), formatScale(plan.Scale), true)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("scale"), "Unable to scale", "Unable to scale. "+err.Error())
+		}
+	}
+
+	// dokku refuses to deploy, rebuild and restart locked app
+	unlock()
+
+	deployed := false
+	if plan.Deploy != nil && !resp.Diagnostics.HasError() {
+		deployed, err = r.deploy(ctx, plan.AppName.ValueString(), *plan.Deploy)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("deploy"), "Unable to deploy", "Unable to deploy. "+err.Error())
+		}
+	}
+
+	// clone is deployed with settings of source app, restart applies overridden ones.
+	// Deploy of the same image as source app is skipped by dokku, so restart is required too
+	clonedDeployed := !plan.CloneFrom.IsNull() && !plan.CloneSkipDeploy.ValueBool()
+	if clonedDeployed && !deployed && (plan.Running.IsNull() || plan.Running.ValueBool()) && !resp.Diagnostics.HasError() {
+		err := r.client.ProcessRestart(ctx, plan.AppName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to restart app", "Unable to restart app. "+err.Error())
+		}
+	}
+
+	if !resp.Diagnostics.HasError() {
+		var deployed bool
+		deployed, plan.DeployedImage, plan.DeployedRevision, err = r.readDeployment(ctx, plan.AppName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("deploy"), "Unable to get deployment", "Unable to get deployment. "+err.Error())
+		}
+
+		if deployed && !plan.Running.IsNull() && !plan.Running.ValueBool() {
+			err = r.client.ProcessStop(ctx, plan.AppName.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("running"), "Unable to stop app", "Unable to stop app. "+err.Error())
+			}
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		err := r.client.AppDestroy(ctx, plan.AppName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to destroy app", "Unable to destroy app. "+err.Error())
+		}
+		return
+	}
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *appResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan appResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var state appResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	lockedName := state.AppName.ValueString()
+	unlock := func() {}
+	if plan.LockDuringApply.ValueBool() {
+		unlock = r.lockApp(ctx, &lockedName, &resp.Diagnostics)
+		defer unlock()
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	appName := plan.AppName.ValueString()
+	if appName != state.AppName.ValueString() {
+		exists, err := r.client.AppExists(ctx, appName)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to check app existence", "Unable to check app existence. "+err.Error())
+			return
+		}
+		if exists {
+			resp.Diagnostics.AddAttributeError(path.Root("app_name"), "App already exists", "App already exists")
+			return
+		}
+
+		err = r.client.AppRename(ctx, state.AppName.ValueString(), appName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("app_name"), "Unable to rename app", "Unable to rename app. "+err.Error())
+			return
+		}
+		lockedName = appName
+		if plan.LockDuringApply.ValueBool() {
+			r.relockApp(ctx, appName, &resp.Diagnostics)
+		}
+
+		// state is saved with new name even if later steps fail, otherwise renamed app is lost on refresh
+		renamedState := state
+		renamedState.AppName = plan.AppName
+		resp.Diagnostics.Append(resp.State.Set(ctx, renamedState)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	restartRequired := false
+	proxyBuildRequired := false
+
+	// -- config
+	var namesToUnset []string
+	for _, stateConfig := range []map[string]types.String{state.Config, state.SensitiveConfig} {
+		for stateName := range stateConfig {
+			_, inPlan := plan.Config[stateName]
+			_, inPlanSensitive := plan.SensitiveConfig[stateName]
+			if !inPlan && !inPlanSensitive {
+				namesToUnset = append(namesToUnset, stateName)
+			}
+		}
+	}
+	// unknown vars are not in state yet when switching to exclusive mode
+	if plan.ConfigMode.ValueString() == "exclusive" && state.ConfigMode.ValueString() != "exclusive" {
+		config, err := r.client.ConfigExport(ctx, appName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to get config", "Unable to get config. "+err.Error())
+		}
+		for name := range config {
+			_, inPlan := plan.Config[name]
+			_, inPlanSensitive := plan.SensitiveConfig[name]
+			_, inState := state.Config[name]
+			_, inStateSensitive := state.SensitiveConfig[name]
+			if !inPlan && !inPlanSensitive && !inState && !inStateSensitive && !isDokkuManagedConfigKey(name, config, plan.Git, state.Git) {
+				namesToUnset = append(namesToUnset, name)
+			}
+		}
+	}
+	if len(namesToUnset) != 0 {
+		err := r.client.ConfigUnset(ctx, appName, namesToUnset)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to unset config", "Unable to unset config. "+err.Error())
+		}
+		restartRequired = true
+	}
+
+	configToSet := make(map[string]string)
+	for k, v := range plan.Config {
+		if !state.Config[k].Equal(v) {
+			configToSet[k] = v.ValueString()
+		}
+	}
+	sensitiveConfigToSet := make(map[string]string)
+	for k, v := range plan.SensitiveConfig {
+		if !state.SensitiveConfig[k].Equal(v) {
+			sensitiveConfigToSet[k] = v.ValueString()
+		}
+	}
+	if len(configToSet) != 0 || len(sensitiveConfigToSet) != 0 {
+		err := r.client.ConfigSet(ctx, appName, configToSet, sensitiveConfigToSet)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to set config", "Unable to set config. "+err.Error())
+		}
+		restartRequired = true
+	}
+	// --
+
+	// -- storage
+	for existingName, existingStorage := range state.Storage {
+		found := false
+		for planName, planStorage := range plan.Storage {
+			if existingName == planName {
+				found = true
+
+				if !existingStorage.MountPath.Equal(planStorage.MountPath) || strings.Join(formatStorageMountOptions(existingStorage), ",") != strings.Join(formatStorageMountOptions(planStorage), ",") {
+					err := r.client.StorageUnmount(ctx, appName, existingName, existingStorage.MountPath.ValueString(), formatStorageMountOptions(existingStorage))
+					if err != nil {
+						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName), "Unable to unmount storage", "Unable to unmount storage. "+err.Error())
+					}
+
+					err = r.client.StorageEnsure(ctx, planName, "", planStorage.LocalDirectory.ValueStringPointer())
+					if err != nil {
+						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName), "Unable to ensure storage", "Unable to ensure storage. "+err.Error())
+					}
+
+					err = r.client.StorageMount(ctx, appName, planName, planStorage.MountPath.ValueString(), formatStorageMountOptions(planStorage))
+					if err != nil {
+						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName), "Unable to mount storage", "Unable to mount storage. "+err.Error())
+					}
+
+					restartRequired = true
+				} else if !planStorage.LocalDirectory.IsNull() {
+					err := r.client.StorageEnsure(ctx, planName, "", planStorage.LocalDirectory.ValueStringPointer())
+					if err != nil {
+						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName), "Unable to ensure storage", "Unable to ensure storage. "+err.Error())
+					}
+
+					restartRequired = true
+				}
+
+				existingFiles := existingStorage.Files
+				if !planStorage.LocalDirectory.IsNull() {
+					// local_directory was uploaded again and ownership of all files was restored - upload all files too
+					existingFiles = nil
+				}
+				filesChanged, err := syncStorageFiles(ctx, r.client, planName, planStorage.Files, existingFiles)
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName).AtName("files"), "Unable to upload storage files", "Unable to upload storage files. "+err.Error())
+				}
+				if filesChanged {
+					restartRequired = true
+				}
+
+				break
+			}
+		}
+		if !found {
+			err := r.client.StorageUnmount(ctx, appName, existingName, existingStorage.MountPath.ValueString(), formatStorageMountOptions(existingStorage))
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName), "Unable to unmount storage", "Unable to unmount storage. "+err.Error())
+			}
+
+			restartRequired = true
+		}
+	}
+	for planName, planStorage := range plan.Storage {
+		found := false
+		for existingName := range state.Storage {
+			if existingName == planName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			err := r.client.StorageEnsure(ctx, planName, "", planStorage.LocalDirectory.ValueStringPointer())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(planName), "Unable to ensure storage", "Unable to ensure storage. "+err.Error())
+			}
+
+			_, err = syncStorageFiles(ctx, r.client, planName, planStorage.Files, nil)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(planName).AtName("files"), "Unable to upload storage files", "Unable to upload storage files. "+err.Error())
+			}
+
+			err = r.client.StorageMount(ctx, appName, planName, planStorage.MountPath.ValueString(), formatStorageMountOptions(planStorage))
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(planName), "Unable to mount storage", "Unable to mount storage. "+err.Error())
+			}
+
+			restartRequired = true
+		}
+	}
+	// --
+
+	// -- checks
+	r.setChecks(ctx, appName, plan.Checks, state.Checks, &resp.Diagnostics)
+	// --
+
+	// -- ports
+	if len(plan.ProxyPorts) > 0 {
+		resp.Diagnostics.AddAttributeWarning(path.Root("proxy_ports"), "proxy_ports attribute is deprecated, use porst attribute instead", "proxy_ports attribute is deprecated, use porst attribute instead")
+	}
+	needToSetPorts := false
+	var portsToSet []dokkuclient.Port
+	for existingHostPort, existingPort := range state.Ports {
+		found := false
+		for planHostPort, planPort := range plan.Ports {
+			if planHostPort == existingHostPort {
+				if planPort.Scheme.Equal(existingPort.Scheme) && planPort.ContainerPort.Equal(existingPort.ContainerPort) {
+					found = true
+				}
+				break
+			}
+		}
+		for planHostPort, planPort := range plan.ProxyPorts {
+			if planHostPort == existingHostPort {
+				if planPort.Scheme.Equal(existingPort.Scheme) && planPort.ContainerPort.Equal(existingPort.ContainerPort) {
+					found = true
+				}
+				break
+			}
+		}
+		if !found {
+			needToSetPorts = true
+		}
+	}
+	for planHostPort, planPort := range plan.Ports {
+		found := false
+		for existingHostPort, existingPort := range state.Ports {
+			if planHostPort == existingHostPort {
+				if planPort.Scheme.Equal(existingPort.Scheme) && planPort.ContainerPort.Equal(existingPort.ContainerPort) {
+					found = true
+				}
+				break
+			}
+		}
+		if !found {
+			needToSetPorts = true
+		}
+		portsToSet = append(portsToSet, dokkuclient.Port{
+			Scheme:        planPort.Scheme.ValueString(),
+			HostPort:      planHostPort,
+			ContainerPort: planPort.ContainerPort.ValueString(),
+		})
+	}
+	for planHostPort, planPort := range plan.ProxyPorts {
+		found := false
+		for existingHostPort, existingPort := range state.Ports {
+			if planHostPort == existingHostPort {
+				if planPort.Scheme.Equal(existingPort.Scheme) && planPort.ContainerPort.Equal(existingPort.ContainerPort) {
+					found = true
+				}
+				break
+			}
+		}
+		if !found {
+			needToSetPorts = true
+		}
+		portsToSet = append(portsToSet, dokkuclient.Port{
+			Scheme:        planPort.Scheme.ValueString(),
+			HostPort:      planHostPort,
+			ContainerPort: planPort.ContainerPort.ValueString(),
+		})
+	}
+	if needToSetPorts {
+		if len(portsToSet) == 0 {
+			err := r.client.PortsClear(ctx, appName)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to clear ports", "Unable to clear ports. "+err.Error())
+			}
+
+			err = r.client.ProxyDisable(ctx, appName)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to disable ports", "Unable to disable ports. "+err.Error())
+			}
+		} else {
+			err := r.client.PortsSet(ctx, appName, portsToSet)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to set ports", "Unable to set ports. "+err.Error())
+			}
+
+			err = r.client.ProxyEnable(ctx, appName)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to enable ports", "Unable to enable ports. "+err.Error())
+			}
+		}
+	}
+	// --
+
+	// -- proxy
+	if plan.Proxy != nil || state.Proxy != nil {
+		var planProxy, stateProxy proxyModel
+		if plan.Proxy != nil {
+			planProxy = *plan.Proxy
+		}
+		if state.Proxy != nil {
+			stateProxy = *state.Proxy
+		}
+		// ports setup enables or disables proxy
+		if needToSetPorts {
+			stateProxy.Enabled = basetypes.NewBoolNull()
+		}
+		if r.setProxy(ctx, appName, planProxy, stateProxy, &resp.Diagnostics) {
+			restartRequired = true
+		}
+	}
+	// --
+
+	// -- domains
+	needToSetDomains := false
+	var domainsToSet []string
+	for _, existingDomain := range state.Domains {
+		found := false
+		for _, planDomain := range plan.Domains {
+			if planDomain == existingDomain {
+				found = true
+				break
+			}
+		}
+		if !found {
+			needToSetDomains = true
+		}
+	}
+	for _, planDomain := range plan.Domains {
+		found := false
+		for _, existingDomain := range state.Domains {
+			if planDomain == existingDomain {
+				found = true
+				break
+			}
+		}
+		if !found {
+			needToSetDomains = true
+		}
+		domainsToSet = append(domainsToSet, planDomain.ValueString())
+	}
+	if needToSetDomains {
+		var err error
+		if len(domainsToSet) == 0 {
+			err = r.client.DomainsDisable(ctx, appName)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to disable domains support", "Unable to disable domains support. "+err.Error())
+			}
+
+			err = r.client.DomainsClear(ctx, appName)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to clear domains", "Unable to clear domains. "+err.Error())
+			}
+		} else {
+			err = r.client.DomainsEnable(ctx, appName)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to enable domains support", "Unable to enable domains support. "+err.Error())
+			}
+
+			err = r.client.DomainsSet(ctx, appName, domainsToSet)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to set domains", "Unable to set domains. "+err.Error())
+			}
+		}
+	}
+	// TODO run letsencrypt:enable again after adding new domains
+	// --
+
+	// -- docker options
+	for existingValue, existingDockerOption := range state.DockerOptions {
+		found := false
+		for planValue, planDockerOption := range plan.DockerOptions {
+			if existingValue == planValue {
+				found = true
+
+				if !existingDockerOption.Phase.Equal(planDockerOption.Phase) {
+					err := r.client.DockerOptionRemove(ctx, appName, formatDockerOptionsPhases(existingDockerOption.Phase), existingValue)
+					if err != nil {
+						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingValue), "Unable to remove docker option", "Unable to remove docker option. "+err.Error())
+					}
+
+					err = r.client.DockerOptionAdd(ctx, appName, formatDockerOptionsPhases(planDockerOption.Phase), planValue)
+					if err != nil {
+						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingValue), "Unable to add docker option", "Unable to add docker option. "+err.Error())
+					}
+
+					restartRequired = true
+				}
+
+				break
+			}
+		}
+		if !found {
+			err := r.client.DockerOptionRemove(ctx, appName, formatDockerOptionsPhases(existingDockerOption.Phase), existingValue)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("docker_options").AtMapKey(existingValue), "Unable to remove docker option", "Unable to remove docker option. "+err.Error())
+			}
+
+			restartRequired = true
+		}
+	}
+	for planValue, planDockerOption := range plan.DockerOptions {
+		found := false
+		for existingValue := range state.DockerOptions {
+			if existingValue == planValue {
+				found = true
+				break
+			}
+		}
+		if !found {
+			err := r.client.DockerOptionAdd(ctx, appName, formatDockerOptionsPhases(planDockerOption.Phase), planValue)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("docker_options").AtMapKey(planValue), "Unable to add docker option", "Unable to add docker option. "+err.Error())
+			}
+
+			restartRequired = true
+		}
+	}
+	// --
+
+	// -- networks
+	if state.Networks != nil {
+		if plan.Networks != nil {
+			if !plan.Networks.AttachPostCreate.Equal(state.Networks.AttachPostCreate) {
+				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "attach-post-create", plan.Networks.AttachPostCreate.ValueString())
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_create"), "Unable to set network", "Unable to set network. "+err.Error())
+				}
+			}
+			if !plan.Networks.AttachPostDeploy.Equal(state.Networks.AttachPostDeploy) {
+				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "attach-post-deploy", plan.Networks.AttachPostDeploy.ValueString())
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_deploy"), "Unable to set network", "Unable to set network. "+err.Error())
+				}
+			}
+			if !plan.Networks.InitialNetwork.Equal(state.Networks.InitialNetwork) {
+				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "initial-network", plan.Networks.InitialNetwork.ValueString())
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("initial_network"), "Unable to set network", "Unable to set network. "+err.Error())
+				}
+			}
+		} else {
+			if !state.Networks.AttachPostCreate.IsNull() {
+				err := r.client.NetworkUnsetForApp(ctx, appName, "attach-post-create")
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_create"), "Unable to unset network", "Unable to unset network. "+err.Error())
+				}
+			}
+			if !state.Networks.AttachPostDeploy.IsNull() {
+				err := r.client.NetworkUnsetForApp(ctx, appName, "attach-post-deploy")
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_deploy"), "Unable to unset network", "Unable to unset network. "+err.Error())
+				}
+			}
+			if !state.Networks.InitialNetwork.IsNull() {
+				err := r.client.NetworkUnsetForApp(ctx, appName, "initial-network")
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("initial_network"), "Unable to unset network", "Unable to unset network. "+err.Error())
+				}
+			}
+		}
+	} else {
+		if plan.Networks != nil {
+			if !plan.Networks.AttachPostCreate.IsNull() {
+				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "attach-post-create", plan.Networks.AttachPostCreate.ValueString())
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_create"), "Unable to set network", "Unable to set network. "+err.Error())
+				}
+			}
+			if !plan.Networks.AttachPostDeploy.IsNull() {
+				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "attach-post-deploy", plan.Networks.AttachPostDeploy.ValueString())
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_deploy"), "Unable to set network", "Unable to set network. "+err.Error())
+				}
+			}
+			if !plan.Networks.InitialNetwork.IsNull() {
+				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "initial-network", plan.Networks.InitialNetwork.ValueString())
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("initial_network"), "Unable to set network", "Unable to set network. "+err.Error())
+				}
+			}
+		}
+	}
+	// --
+
+	// -- build
+	r.setAppProperties(ctx, appName, path.Root("build"), buildProperties(plan.Build), buildProperties(state.Build), &resp.Diagnostics)
+	// --
+
+	// -- buildpacks
+	planBuildpacks := formatStrings(plan.Buildpacks)
+	if strings.Join(planBuildpacks, ",") != strings.Join(formatStrings(state.Buildpacks), ",") {
+		// buildpacks are cleared and added again to keep order
+		err := r.client.BuildpacksSet(ctx, appName, planBuildpacks)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("buildpacks"), "Unable to set buildpacks", "Unable to set buildpacks. "+err.Error())
+		}
+	}
+	// --
+
+	// -- nginx
+	planNginx := nginxProperties(plan.Nginx)
+	stateNginx := nginxProperties(state.Nginx)
+	r.setAppProperties(ctx, appName, path.Root("nginx"), planNginx, stateNginx, &resp.Diagnostics)
+	for i := range planNginx {
+		if !planNginx[i].value.Equal(stateNginx[i].value) {
+			proxyBuildRequired = true
+		}
+	}
+	// --
+
+	// -- process
+	planProcess := processProperties(plan.Process)
+	stateProcess := processProperties(state.Process)
+	r.setAppProperties(ctx, appName, path.Root("process"), planProcess, stateProcess, &resp.Diagnostics)
+	// restart policy is applied to new containers only
+	if !planProcess[0].value.Equal(stateProcess[0].value) {
+		restartRequired = true
+	}
+	// --
+
+	// -- logs
+	planLogs := logsProperties(plan.Logs)
+	stateLogs := logsProperties(state.Logs)
+	r.setAppProperties(ctx, appName, path.Root("logs"), planLogs, stateLogs, &resp.Diagnostics)
+	// max size is applied to new containers only
+	if !planLogs[1].value.Equal(stateLogs[1].value) {
+		restartRequired = true
+	}
+	// --
+
+	// -- scheduler
+	r.setScheduler(ctx, appName, plan.Scheduler, state.Scheduler, &resp.Diagnostics)
+	// --
+
+	// -- git
+	r.setAppProperties(ctx, appName, path.Root("git"), gitProperties(plan.Git), gitProperties(state.Git), &resp.Diagnostics)
+	r.clearSourceImage(ctx, appName, plan.Git, plan.Deploy, &resp.Diagnostics)
+	// previous rev env var is not updated by dokku anymore, unless it is managed as config
+	prevRevEnvVar := gitRevEnvVar(state.Git)
+	_, inPlan := plan.Config[prevRevEnvVar]
+	_, inPlanSensitive := plan.SensitiveConfig[prevRevEnvVar]
+	if prevRevEnvVar != gitRevEnvVar(plan.Git) && prevRevEnvVar != "GIT_REV" && !inPlan && !inPlanSensitive {
+		err := r.client.ConfigUnset(ctx, appName, []string{prevRevEnvVar})
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("git").AtName("rev_env_var"), "Unable to unset previous rev env var", "Unable to unset previous rev env var. "+err.Error())
+		}
+	}
+	// --
+
+	// -- resources
+	for processType, planResources := range plan.Resources {
+		stateResources := state.Resources[processType]
+		if !resourceValuesEqual(planResources.Limit, stateResources.Limit) {
+			err := r.client.ResourceSet(ctx, appName, "limit", processType, formatResourceValues(planResources.Limit))
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("resources").AtMapKey(processType).AtName("limit"), "Unable to set resource limits", "Unable to set resource limits. "+err.Error())
+			}
+			restartRequired = true
+		}
+		if !resourceValuesEqual(planResources.Reserve, stateResources.Reserve) {
+			err := r.client.ResourceSet(ctx, appName, "reserve", processType, formatResourceValues(planResources.Reserve))
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("resources").AtMapKey(processType).AtName("reserve"), "Unable to set resource reservations", "Unable to set resource reservations. "+err.Error())
+			}
+			restartRequired = true
+		}
+	}
+	// --
+
+	// -- deploy
+	// dokku refuses to deploy, rebuild and restart locked app
+	unlock()
+
+	if plan.Deploy != nil {
+		deployed, err := r.deploy(ctx, plan.AppName.ValueString(), *plan.Deploy)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("deploy"), "Unable to deploy", "Unable to deploy. "+err.Error())
+		}
+		if deployed {
+			restartRequired = false
+			proxyBuildRequired = false
+		}
+	}
+
+	deployed, deployedImage, deployedRevision, err := r.readDeployment(ctx, appName)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deploy"), "Unable to get deployment", "Unable to get deployment. "+err.Error())
+	}
+	plan.DeployedImage = deployedImage
+	plan.DeployedRevision = deployedRevision
+	// --
+
+	// -- scale
+	scaleToSet := make(map[string]types.Int64)
+	for processType, count := range plan.Scale {
+		if !state.Scale[processType].Equal(count) {
+			scaleToSet[processType] = count
+		}
+	}
+	if len(scaleToSet) != 0 {
+		// scale is applied to running containers only for deployed app, which should not be stopped
+		skipDeploy := !deployed || (!plan.Running.IsNull() && !plan.Running.ValueBool())
+		err := r.client.ProcessScale(ctx, appName, formatScale(scaleToSet), skipDeploy)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("scale"), "Unable to scale", "Unable to scale. "+err.Error())
+		}
+	}
+	// --
+
+	// -- running
+	if !resp.Diagnostics.HasError() && deployed {
+		running, _, err := r.isRunning(ctx, appName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("running"), "Unable to get running state", "Unable to get running state. "+err.Error())
+		}
+
+		switch {
+		case err != nil:
+		case !plan.Running.IsNull() && !plan.Running.ValueBool():
+			// stopped app is never started by restart
+			restartRequired = false
+			if running {
+				err = r.client.ProcessStop(ctx, appName)
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("running"), "Unable to stop app", "Unable to stop app. "+err.Error())
+				}
+			}
+		case !plan.Running.IsNull() && plan.Running.ValueBool():
+			// restart starts app too
+			if !running && !restartRequired {
+				err = r.client.ProcessStart(ctx, appName)
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("running"), "Unable to start app", "Unable to start app. "+err.Error())
+				}
+			}
+		default:
+			// app stopped outside of terraform is kept stopped
+			if !running {
+				restartRequired = false
+			}
+		}
+	}
+	// --
+
+	if !resp.Diagnostics.HasError() && restartRequired {
+		err := r.client.ProcessRestart(ctx, appName)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to restart process", "Unable to restart process. "+err.Error())
+		}
+	}
+	// proxy config is built on restart too
+	if !resp.Diagnostics.HasError() && proxyBuildRequired && !restartRequired && deployed {
+		err := r.client.ProxyBuildConfig(ctx, appName)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to build proxy config", "Unable to build proxy config. "+err.Error())
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *appResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state appResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	exists, err := r.client.AppExists(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("app_name"), "Unable to check app existence", "Unable to check app existence. "+err.Error())
+		return
+	}
+	if !exists {
+		return
+	}
+
+	// Delete existing app
+	err = r.client.AppDestroy(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to destroy app", "Unable to destroy app. "+err.Error())
+		return
+	}
+}
+
+// ModifyPlan warns about app rename and keeps deployed_* attributes known if deploy will not change them.
+func (r *appResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to do on create and destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan appResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	var state appResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.AppName.IsUnknown() && plan.AppName.ValueString() != state.AppName.ValueString() {
+		resp.Diagnostics.AddAttributeWarning(path.Root("app_name"), "App will be renamed", fmt.Sprintf("App %s will be renamed to %s with apps:rename. Deployed app will be rebuilt", state.AppName.ValueString(), plan.AppName.ValueString()))
+	}
+
+	if plan.Deploy != nil || state.Deploy != nil {
+		if plan.Deploy == nil || state.Deploy == nil || *plan.Deploy != *state.Deploy {
+			return
+		}
+		// deploy is run on every apply. Deployed source is known only for docker image and git sha,
+		// git:sync can fetch new commits of branch or tag and archive can be changed at the same url
+		switch plan.Deploy.Type.ValueString() {
+		case "docker_image":
+		case "git_repository":
+			if !isGitSha(plan.Deploy.GitRepositoryRef.ValueString()) {
+				return
+			}
+		default:
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("deployed_revision"), state.DeployedRevision)...)
+
+	// source image is unset on apply, deployed image is not known until app is read again
+	if plan.Git != nil && (plan.Git.ClearSourceImage.IsUnknown() || plan.Git.ClearSourceImage.ValueBool()) &&
+		!isDockerImageDeploy(plan.Deploy) && state.DeployedImage.ValueString() != "" {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("deployed_image"), state.DeployedImage)...)
+}
+
+func (r *appResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to app_name attribute
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app_name"), req.ID)...)
+}
+
+func (r *appResource) deploy(ctx context.Context, appName string, deployModel deployModel) (deployed bool, err error) {
+	switch deployModel.Type.ValueString() {
+	case "archive":
+		err = r.client.DeployFromArchive(ctx, appName, deployModel.ArchiveType.ValueString(), deployModel.ArchiveUrl.ValueString())
+		deployed = err == nil
+	case "docker_image":
+		if !deployModel.Login.IsNull() && !deployModel.Password.IsNull() {
+			u, err := url.Parse("https://" + deployModel.DockerImage.ValueString())
+			if err != nil {
+				return false, fmt.Errorf("unable to parse url: %w", err)
+			}
+			err = r.client.RegistryLogin(ctx, u.Host, deployModel.Login.ValueString(), deployModel.Password.ValueString())
+			if err != nil {
+				return false, fmt.Errorf("unable to login to registry: %w", err)
+			}
+		}
 
-	if resp.Diagnostics.HasError() {
-		return
-	}
+		deployed, err = r.client.DeployFromImage(ctx, appName, deployModel.DockerImage.ValueString(), deployModel.AllowRebuild.ValueBool())
+	case "git_repository":
+		if !deployModel.Login.IsNull() && !deployModel.Password.IsNull() {
+			u, err := url.Parse(deployModel.GitRepository.ValueString())
+			if err != nil {
+				return false, fmt.Errorf("unable to parse url: %w", err)
+			}
+			err = r.client.GitAuth(ctx, u.Host, deployModel.Login.ValueString(), deployModel.Password.ValueString())
+			if err != nil {
+				return false, fmt.Errorf("unable to login to git: %w", err)
+			}
+		}
 
-	// Set refreshed state
-	diags = resp.State.Set(ctx, &state)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+		err = r.client.DeploySyncRepository(ctx, appName, deployModel.GitRepository.ValueString(), deployModel.GitRepositoryRef.ValueString())
+		deployed = err == nil
+	default:
+		err = fmt.Errorf("Unknown deploy type %s", deployModel.Type.ValueString())
 	}
+	return
 }
 
-// Create creates the resource and sets the initial Terraform state.
-func (r *appResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	// Retrieve values from plan
-	var plan appResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
+// readDeployment returns deployed source of app using ps:report and git:report
+func (r *appResource) readDeployment(ctx context.Context, appName string) (deployed bool, image types.String, revision types.String, err error) {
+	image = basetypes.NewStringNull()
+	revision = basetypes.NewStringNull()
 
-	// Check app existence
-	exists, err := r.client.AppExists(ctx, plan.AppName.ValueString())
+	processReport, err := r.client.ProcessReport(ctx, appName)
 	if err != nil {
-		resp.Diagnostics.AddAttributeError(path.Root("app_name"), "Unable to check app existence", "Unable to check app existence. "+err.Error())
-		return
+		return false, image, revision, err
 	}
-	if exists {
-		resp.Diagnostics.AddAttributeError(path.Root("app_name"), "App already exists", "App with specified name already exists")
-		return
+	if processReport["Deployed"] != "true" {
+		return false, image, revision, nil
 	}
 
-	// Create new app
-	err = r.client.AppCreate(ctx, plan.AppName.ValueString())
+	gitReport, err := r.client.GitReport(ctx, appName)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to create app", "Unable to create app. "+err.Error())
-		// if not created - return to not try to destroy on other errors
-		return
+		return false, image, revision, err
+	}
+	if gitReport["source image"] != "" {
+		image = basetypes.NewStringValue(gitReport["source image"])
 	}
+	if gitReport["sha"] != "" {
+		revision = basetypes.NewStringValue(gitReport["sha"])
+	}
+	return true, image, revision, nil
+}
 
-	if len(plan.Config) != 0 {
-		config := make(map[string]string)
-		for k, v := range plan.Config {
-			config[k] = v.ValueString()
-		}
-		err := r.client.ConfigSet(ctx, plan.AppName.ValueString(), config)
-		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to set config", "Unable to set config. "+err.Error())
-		}
+// nginxProperties returns properties of nginx plugin with values from model
+func nginxProperties(nginx *nginxModel) []appProperty {
+	if nginx == nil {
+		nginx = &nginxModel{}
+	}
+	return []appProperty{
+		{"nginx", "client-max-body-size", "client_max_body_size", nginx.ClientMaxBodySize},
+		{"nginx", "proxy-connect-timeout", "proxy_connect_timeout", nginx.ProxyConnectTimeout},
+		{"nginx", "proxy-read-timeout", "proxy_read_timeout", nginx.ProxyReadTimeout},
+		{"nginx", "proxy-send-timeout", "proxy_send_timeout", nginx.ProxySendTimeout},
+		{"nginx", "hsts", "hsts", formatBool(nginx.Hsts)},
+		{"nginx", "hsts-include-subdomains", "hsts_include_subdomains", formatBool(nginx.HstsIncludeSubdomains)},
+		{"nginx", "hsts-max-age", "hsts_max_age", formatInt64(nginx.HstsMaxAge)},
+		{"nginx", "hsts-preload", "hsts_preload", formatBool(nginx.HstsPreload)},
+		{"nginx", "access-log-format", "access_log_format", nginx.AccessLogFormat},
+		{"nginx", "access-log-path", "access_log_path", nginx.AccessLogPath},
+		{"nginx", "error-log-path", "error_log_path", nginx.ErrorLogPath},
+		{"nginx", "bind-address-ipv4", "bind_address_ipv4", nginx.BindAddressIpv4},
+		{"nginx", "bind-address-ipv6", "bind_address_ipv6", nginx.BindAddressIpv6},
+		{"nginx", "x-forwarded-for-value", "x_forwarded_for_value", nginx.XForwardedForValue},
+		{"nginx", "x-forwarded-port-value", "x_forwarded_port_value", nginx.XForwardedPortValue},
+		{"nginx", "x-forwarded-proto-value", "x_forwarded_proto_value", nginx.XForwardedProtoValue},
+		{"nginx", "x-forwarded-ssl", "x_forwarded_ssl", nginx.XForwardedSsl},
 	}
+}
 
-	for hostPath, storage := range plan.Storage {
-		err := r.client.StorageEnsure(ctx, hostPath, storage.LocalDirectory.ValueStringPointer())
-		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(hostPath), "Unable to ensure storage", "Unable to ensure storage. "+err.Error())
-		}
+func (r *appResource) readNginx(ctx context.Context, appName string, state *nginxModel) (*nginxModel, error) {
+	if state == nil {
+		return nil, nil
+	}
 
-		err = r.client.StorageMount(ctx, plan.AppName.ValueString(), hostPath, storage.MountPath.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(hostPath), "Unable to mount storage", "Unable to mount storage. "+err.Error())
-		}
+	values, err := r.readAppProperties(ctx, appName, nginxProperties(state))
+	if err != nil {
+		return nil, err
 	}
 
-	if plan.Checks != nil {
-		if !plan.Checks.Status.IsNull() {
-			err := r.client.ChecksSet(ctx, plan.AppName.ValueString(), plan.Checks.Status.ValueString())
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("checks"), "Unable to set checks", "Unable to set checks. "+err.Error())
-			}
-		}
+	nginx := &nginxModel{
+		ClientMaxBodySize:     values["client_max_body_size"],
+		ProxyConnectTimeout:   values["proxy_connect_timeout"],
+		ProxyReadTimeout:      values["proxy_read_timeout"],
+		ProxySendTimeout:      values["proxy_send_timeout"],
+		Hsts:                  parseBool(values["hsts"]),
+		HstsIncludeSubdomains: parseBool(values["hsts_include_subdomains"]),
+		HstsMaxAge:            parseInt64(values["hsts_max_age"]),
+		HstsPreload:           parseBool(values["hsts_preload"]),
+		AccessLogFormat:       values["access_log_format"],
+		AccessLogPath:         values["access_log_path"],
+		ErrorLogPath:          values["error_log_path"],
+		BindAddressIpv4:       values["bind_address_ipv4"],
+		BindAddressIpv6:       values["bind_address_ipv6"],
+		XForwardedForValue:    values["x_forwarded_for_value"],
+		XForwardedPortValue:   values["x_forwarded_port_value"],
+		XForwardedProtoValue:  values["x_forwarded_proto_value"],
+		XForwardedSsl:         values["x_forwarded_ssl"],
+	}
+	if *nginx == (nginxModel{}) {
+		return nil, nil
 	}
+	return nginx, nil
+}
 
-	if len(plan.Ports) != 0 || len(plan.ProxyPorts) != 0 {
-		if len(plan.ProxyPorts) > 0 {
-			resp.Diagnostics.AddAttributeWarning(path.Root("proxy_ports"), "proxy_ports attribute is deprecated, use porst attribute instead", "proxy_ports attribute is deprecated, use porst attribute instead")
-		}
+// readProxy returns proxy setup. Only attributes and keys known in state are read
+func (r *appResource) readProxy(ctx context.Context, appName string, state proxyModel) (*proxyModel, error) {
+	report, err := r.client.AppPropertiesReport(ctx, "proxy", appName)
+	if err != nil {
+		return nil, err
+	}
 
-		var ports []dokkuclient.Port
-		for hostPort, port := range plan.Ports {
-			ports = append(ports, dokkuclient.Port{
-				Scheme:        port.Scheme.ValueString(),
-				HostPort:      hostPort,
-				ContainerPort: port.ContainerPort.ValueString(),
-			})
-		}
-		for hostPort, port := range plan.ProxyPorts {
-			ports = append(ports, dokkuclient.Port{
-				Scheme:        port.Scheme.ValueString(),
-				HostPort:      hostPort,
-				ContainerPort: port.ContainerPort.ValueString(),
-			})
-		}
-		err := r.client.PortsSet(ctx, plan.AppName.ValueString(), ports)
-		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to set ports", "Unable to set ports. "+err.Error())
-		}
-		err = r.client.ProxyEnable(ctx, plan.AppName.ValueString())
+	proxy := proxyModel{
+		Type:    basetypes.NewStringNull(),
+		Enabled: basetypes.NewBoolNull(),
+	}
+	if !state.Type.IsNull() && report["type"] != "" {
+		proxy.Type = basetypes.NewStringValue(report["type"])
+	}
+	if !state.Enabled.IsNull() {
+		proxy.Enabled = parseBool(basetypes.NewStringValue(report["enabled"]))
+	}
+
+	proxyType := state.Type.ValueString()
+	for label := range state.Labels {
+		value, err := r.client.ProxyLabelGet(ctx, proxyType, appName, label)
 		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to enable ports", "Unable to enable ports. "+err.Error())
+			return nil, err
 		}
-	} else {
-		err = r.client.ProxyDisable(ctx, plan.AppName.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to disable ports", "Unable to disable ports. "+err.Error())
+		if value != "" {
+			if proxy.Labels == nil {
+				proxy.Labels = make(map[string]types.String)
+			}
+			proxy.Labels[label] = basetypes.NewStringValue(value)
 		}
 	}
-
-	if len(plan.Domains) != 0 {
-		var domains []string
-		for _, domain := range plan.Domains {
-			domains = append(domains, domain.ValueString())
-		}
-		err := r.client.DomainsSet(ctx, plan.AppName.ValueString(), domains)
+	if len(state.Properties) != 0 {
+		properties, err := r.client.AppPropertiesReport(ctx, proxyType, appName)
 		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to add domain", "Unable to add domain. "+err.Error())
+			return nil, err
 		}
-		err = r.client.DomainsEnable(ctx, plan.AppName.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to enable domains support", "Unable to enable domains support. "+err.Error())
+		for key := range state.Properties {
+			if value := properties[strings.ReplaceAll(key, "-", " ")]; value != "" {
+				if proxy.Properties == nil {
+					proxy.Properties = make(map[string]types.String)
+				}
+				proxy.Properties[key] = basetypes.NewStringValue(value)
+			}
 		}
-	} else {
-		err = r.client.DomainsDisable(ctx, plan.AppName.ValueString())
+	}
+
+	if proxy.Type.IsNull() && proxy.Enabled.IsNull() && proxy.Labels == nil && proxy.Properties == nil {
+		return nil, nil
+	}
+	return &proxy, nil
+}
+
+// setProxy applies changed proxy setup. Returns true if app restart is required to apply changes
+func (r *appResource) setProxy(ctx context.Context, appName string, plan proxyModel, state proxyModel, diags *diag.Diagnostics) (restartRequired bool) {
+	proxyPath := path.Root("proxy")
+
+	if !plan.Type.Equal(state.Type) {
+		err := r.client.ProxySetType(ctx, appName, plan.Type.ValueString())
 		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to disable domains support", "Unable to disable domains support. "+err.Error())
+			diags.AddAttributeError(proxyPath.AtName("type"), "Unable to set proxy type", "Unable to set proxy type. "+err.Error())
 		}
+		restartRequired = true
 	}
 
-	for option, dockerOption := range plan.DockerOptions {
-		err := r.client.DockerOptionAdd(ctx, plan.AppName.ValueString(), formatDockerOptionsPhases(dockerOption.Phase), option)
+	if !plan.Enabled.IsNull() && !plan.Enabled.Equal(state.Enabled) {
+		var err error
+		if plan.Enabled.ValueBool() {
+			err = r.client.ProxyEnable(ctx, appName)
+		} else {
+			err = r.client.ProxyDisable(ctx, appName)
+		}
 		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("docker_options").AtMapKey(option), "Unable to add docker option", "Unable to add docker option. "+err.Error())
+			diags.AddAttributeError(proxyPath.AtName("enabled"), "Unable to set proxy enabled", "Unable to set proxy enabled. "+err.Error())
 		}
 	}
 
-	if plan.Networks != nil {
-		if !plan.Networks.AttachPostCreate.IsNull() {
-			err := r.client.NetworkEnsureAndSetForApp(ctx, plan.AppName.ValueString(), "attach-post-create", plan.Networks.AttachPostCreate.ValueString())
+	// labels and properties of previous proxy type are removed on type change
+	for label := range state.Labels {
+		if _, ok := plan.Labels[label]; !ok || !plan.Type.Equal(state.Type) {
+			err := r.client.ProxyLabelRemove(ctx, state.Type.ValueString(), appName, label)
 			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_create"), "Unable to set network", "Unable to set network. "+err.Error())
+				diags.AddAttributeError(proxyPath.AtName("labels").AtMapKey(label), "Unable to remove proxy label", "Unable to remove proxy label. "+err.Error())
 			}
+			restartRequired = true
 		}
-		if !plan.Networks.AttachPostDeploy.IsNull() {
-			err := r.client.NetworkEnsureAndSetForApp(ctx, plan.AppName.ValueString(), "attach-post-deploy", plan.Networks.AttachPostDeploy.ValueString())
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_deploy"), "Unable to set network", "Unable to set network. "+err.Error())
-			}
+	}
+	for label, value := range plan.Labels {
+		if value.Equal(state.Labels[label]) && plan.Type.Equal(state.Type) {
+			continue
 		}
-		if !plan.Networks.InitialNetwork.IsNull() {
-			err := r.client.NetworkEnsureAndSetForApp(ctx, plan.AppName.ValueString(), "initial_network", plan.Networks.InitialNetwork.ValueString())
+		err := r.client.ProxyLabelAdd(ctx, plan.Type.ValueString(), appName, label, value.ValueString())
+		if err != nil {
+			diags.AddAttributeError(proxyPath.AtName("labels").AtMapKey(label), "Unable to add proxy label", "Unable to add proxy label. "+err.Error())
+		}
+		restartRequired = true
+	}
+
+	for key := range state.Properties {
+		if _, ok := plan.Properties[key]; !ok || !plan.Type.Equal(state.Type) {
+			err := r.client.AppPropertySet(ctx, state.Type.ValueString(), appName, key, "")
 			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("initial_network"), "Unable to set network", "Unable to set network. "+err.Error())
+				diags.AddAttributeError(proxyPath.AtName("properties").AtMapKey(key), "Unable to unset proxy property", "Unable to unset proxy property. "+err.Error())
 			}
+			restartRequired = true
 		}
 	}
-
-	if plan.Deploy != nil && !resp.Diagnostics.HasError() {
-		_, err := r.deploy(ctx, plan.AppName.ValueString(), *plan.Deploy)
+	for key, value := range plan.Properties {
+		if value.Equal(state.Properties[key]) && plan.Type.Equal(state.Type) {
+			continue
+		}
+		err := r.client.AppPropertySet(ctx, plan.Type.ValueString(), appName, key, value.ValueString())
 		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("deploy"), "Unable to deploy", "Unable to deploy. "+err.Error())
+			diags.AddAttributeError(proxyPath.AtName("properties").AtMapKey(key), "Unable to set proxy property", "Unable to set proxy property. "+err.Error())
 		}
+		restartRequired = true
 	}
 
-	if resp.Diagnostics.HasError() {
-		err := r.client.AppDestroy(ctx, plan.AppName.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError("Unable to destroy app", "Unable to destroy app. "+err.Error())
-		}
-		return
+	return restartRequired
+}
+
+// processProperties returns properties of ps plugin with values from model. Restart policy is first
+func processProperties(process *processModel) []appProperty {
+	if process == nil {
+		process = &processModel{}
+	}
+	return []appProperty{
+		{"ps", "restart-policy", "restart_policy", process.RestartPolicy},
+		{"ps", "stop-timeout-seconds", "stop_timeout_seconds", formatInt64(process.StopTimeoutSeconds)},
+		{"ps", "procfile-path", "procfile_path", process.ProcfilePath},
 	}
+}
 
-	// Set state to fully populated data
-	diags = resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+func (r *appResource) readProcess(ctx context.Context, appName string, state *processModel) (*processModel, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	values, err := r.readAppProperties(ctx, appName, processProperties(state))
+	if err != nil {
+		return nil, err
+	}
+
+	process := &processModel{
+		RestartPolicy:      values["restart_policy"],
+		StopTimeoutSeconds: parseInt64(values["stop_timeout_seconds"]),
+		ProcfilePath:       values["procfile_path"],
+	}
+	if *process == (processModel{}) {
+		return nil, nil
 	}
+	return process, nil
 }
 
-// Update updates the resource and sets the updated Terraform state on success.
-func (r *appResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Retrieve values from plan
-	var plan appResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+// logsProperties returns properties of logs plugin with values from model. Max size is second
+func logsProperties(logs *logsModel) []appProperty {
+	if logs == nil {
+		logs = &logsModel{}
 	}
-	var state appResourceModel
-	diags = req.State.Get(ctx, &state)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	return []appProperty{
+		{"logs", "vector-sink", "vector_sink", logs.VectorSink},
+		{"logs", "max-size", "max_size", logs.MaxSize},
 	}
+}
 
-	if plan.AppName.ValueString() != state.AppName.ValueString() {
-		resp.Diagnostics.AddAttributeError(path.Root("app_name"), "App name can't be changed", "App name can't be changed")
-		return
+func (r *appResource) readLogs(ctx context.Context, appName string, state *logsModel) (*logsModel, error) {
+	if state == nil {
+		return nil, nil
 	}
-	appName := plan.AppName.ValueString()
 
-	restartRequired := false
+	values, err := r.readAppProperties(ctx, appName, logsProperties(state))
+	if err != nil {
+		return nil, err
+	}
 
-	// -- config
-	var namesToUnset []string
-	for stateName := range state.Config {
-		found := false
-		for planName := range plan.Config {
-			if planName == stateName {
-				found = true
-				break
-			}
-		}
-		if !found {
-			namesToUnset = append(namesToUnset, stateName)
+	logs := &logsModel{
+		VectorSink: values["vector_sink"],
+		MaxSize:    values["max_size"],
+	}
+	if *logs == (logsModel{}) {
+		return nil, nil
+	}
+	return logs, nil
+}
+
+// schedulerProperties returns properties of scheduler plugins with values from model, by attribute path
+func schedulerProperties(scheduler *schedulerModel) map[string][]appProperty {
+	if scheduler == nil {
+		scheduler = &schedulerModel{}
+	}
+	dockerLocal := scheduler.DockerLocal
+	if dockerLocal == nil {
+		dockerLocal = &schedulerDockerModel{}
+	}
+	k3s := scheduler.K3s
+	if k3s == nil {
+		k3s = &schedulerK3sModel{}
+	}
+	return map[string][]appProperty{
+		"": {
+			{"scheduler", "selected", "selected", scheduler.Selected},
+		},
+		"docker_local": {
+			{"scheduler-docker-local", "init-process", "init_process", formatBool(dockerLocal.InitProcess)},
+			{"scheduler-docker-local", "parallel-schedule-count", "parallel_schedule_count", formatInt64(dockerLocal.ParallelScheduleCount)},
+		},
+		"k3s": {
+			{"scheduler-k3s", "namespace", "namespace", k3s.Namespace},
+			{"scheduler-k3s", "deploy-timeout", "deploy_timeout", k3s.DeployTimeout},
+			{"scheduler-k3s", "image-pull-secrets", "image_pull_secrets", k3s.ImagePullSecrets},
+			{"scheduler-k3s", "rollback-on-failure", "rollback_on_failure", formatBool(k3s.RollbackOnFailure)},
+		},
+	}
+}
+
+// setScheduler sets scheduler properties changed in plan. state is nil on create
+func (r *appResource) setScheduler(ctx context.Context, appName string, plan *schedulerModel, state *schedulerModel, diags *diag.Diagnostics) {
+	planProperties := schedulerProperties(plan)
+	var stateProperties map[string][]appProperty
+	if state != nil {
+		stateProperties = schedulerProperties(state)
+	}
+
+	// scheduler is selected first, so its properties are set for scheduler in use
+	for _, block := range []string{"", "docker_local", "k3s"} {
+		attrPath := path.Root("scheduler")
+		if block != "" {
+			attrPath = attrPath.AtName(block)
 		}
+		r.setAppProperties(ctx, appName, attrPath, planProperties[block], stateProperties[block], diags)
+	}
+}
+
+func (r *appResource) readScheduler(ctx context.Context, appName string, state *schedulerModel) (*schedulerModel, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	properties := schedulerProperties(state)
+	values, err := r.readAppProperties(ctx, appName, properties[""])
+	if err != nil {
+		return nil, err
+	}
+	scheduler := &schedulerModel{
+		Selected: values["selected"],
+	}
+
+	values, err = r.readAppProperties(ctx, appName, properties["docker_local"])
+	if err != nil {
+		return nil, err
+	}
+	dockerLocal := schedulerDockerModel{
+		InitProcess:           parseBool(values["init_process"]),
+		ParallelScheduleCount: parseInt64(values["parallel_schedule_count"]),
+	}
+	if dockerLocal != (schedulerDockerModel{}) {
+		scheduler.DockerLocal = &dockerLocal
+	}
+
+	values, err = r.readAppProperties(ctx, appName, properties["k3s"])
+	if err != nil {
+		return nil, err
+	}
+	k3s := schedulerK3sModel{
+		Namespace:         values["namespace"],
+		DeployTimeout:     values["deploy_timeout"],
+		ImagePullSecrets:  values["image_pull_secrets"],
+		RollbackOnFailure: parseBool(values["rollback_on_failure"]),
+	}
+	if k3s != (schedulerK3sModel{}) {
+		scheduler.K3s = &k3s
+	}
+
+	if *scheduler == (schedulerModel{}) {
+		return nil, nil
+	}
+	return scheduler, nil
+}
+
+// gitProperties returns properties of git plugin with values from model
+func gitProperties(git *gitModel) []appProperty {
+	if git == nil {
+		git = &gitModel{}
+	}
+	return []appProperty{
+		{"git", "deploy-branch", "deploy_branch", git.DeployBranch},
+		{"git", "keep-git-dir", "keep_git_dir", formatBool(git.KeepGitDir)},
+		{"git", "rev-env-var", "rev_env_var", git.RevEnvVar},
+	}
+}
+
+// readGit returns git setup. clear_source_image is read as false if source image is still set
+func (r *appResource) readGit(ctx context.Context, appName string, state *gitModel, deploy *deployModel) (*gitModel, error) {
+	if state == nil {
+		return nil, nil
 	}
-	if len(namesToUnset) != 0 {
-		err := r.client.ConfigUnset(ctx, appName, namesToUnset)
-		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to unset config", "Unable to unset config. "+err.Error())
-		}
-		restartRequired = true
+
+	values, err := r.readAppProperties(ctx, appName, gitProperties(state))
+	if err != nil {
+		return nil, err
 	}
 
-	configToSet := make(map[string]string)
-	for k, v := range plan.Config {
-		if !state.Config[k].Equal(v) {
-			configToSet[k] = v.ValueString()
-		}
+	git := &gitModel{
+		DeployBranch:     values["deploy_branch"],
+		KeepGitDir:       parseBool(values["keep_git_dir"]),
+		RevEnvVar:        values["rev_env_var"],
+		ClearSourceImage: state.ClearSourceImage,
 	}
-	if len(configToSet) != 0 {
-		err := r.client.ConfigSet(ctx, appName, configToSet)
+	if state.ClearSourceImage.ValueBool() && !isDockerImageDeploy(deploy) {
+		report, err := r.client.GitReport(ctx, appName)
 		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("config"), "Unable to set config", "Unable to set config. "+err.Error())
+			return nil, err
 		}
-		restartRequired = true
+		git.ClearSourceImage = basetypes.NewBoolValue(report["source image"] == "")
 	}
-	// --
+	if *git == (gitModel{}) {
+		return nil, nil
+	}
+	return git, nil
+}
 
-	// -- storage
-	for existingName, existingStorage := range state.Storage {
-		found := false
-		for planName, planStorage := range plan.Storage {
-			if existingName == planName {
-				found = true
+// clearSourceImage unsets source image if requested and it is set
+func (r *appResource) clearSourceImage(ctx context.Context, appName string, git *gitModel, deploy *deployModel, diags *diag.Diagnostics) {
+	if git == nil || !git.ClearSourceImage.ValueBool() || isDockerImageDeploy(deploy) {
+		return
+	}
 
-				if !existingStorage.MountPath.Equal(planStorage.MountPath) {
-					err := r.client.StorageUnmount(ctx, appName, existingName, existingStorage.MountPath.ValueString())
-					if err != nil {
-						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName), "Unable to unmount storage", "Unable to unmount storage. "+err.Error())
-					}
+	report, err := r.client.GitReport(ctx, appName)
+	if err != nil {
+		diags.AddAttributeError(path.Root("git").AtName("clear_source_image"), "Unable to get git setup", "Unable to get git setup. "+err.Error())
+		return
+	}
+	if report["source image"] == "" {
+		return
+	}
 
-					err = r.client.StorageEnsure(ctx, planName, planStorage.LocalDirectory.ValueStringPointer())
-					if err != nil {
-						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName), "Unable to ensure storage", "Unable to ensure storage. "+err.Error())
-					}
+	err = r.client.DeployUnsetSourceImage(ctx, appName)
+	if err != nil {
+		diags.AddAttributeError(path.Root("git").AtName("clear_source_image"), "Unable to unset source image", "Unable to unset source image. "+err.Error())
+	}
+}
 
-					err = r.client.StorageMount(ctx, appName, planName, planStorage.MountPath.ValueString())
-					if err != nil {
-						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName), "Unable to mount storage", "Unable to mount storage. "+err.Error())
-					}
+func isDockerImageDeploy(deploy *deployModel) bool {
+	return deploy != nil && deploy.Type.ValueString() == "docker_image"
+}
 
-					restartRequired = true
-				} else if !planStorage.LocalDirectory.IsNull() {
-					err := r.client.StorageEnsure(ctx, planName, planStorage.LocalDirectory.ValueStringPointer())
-					if err != nil {
-						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName), "Unable to ensure storage", "Unable to ensure storage. "+err.Error())
-					}
+// requiresReplaceIfChangedString requires replace only if value is changed, not set or removed
+func requiresReplaceIfChangedString(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.StateValue.IsNull() && !req.PlanValue.IsNull()
+}
 
-					restartRequired = true
-				}
+// requiresReplaceIfChangedBool requires replace only if value is changed, not set or removed
+func requiresReplaceIfChangedBool(_ context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.StateValue.IsNull() && !req.PlanValue.IsNull()
+}
 
-				break
-			}
-		}
-		if !found {
-			err := r.client.StorageUnmount(ctx, appName, existingName, existingStorage.MountPath.ValueString())
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingName), "Unable to unmount storage", "Unable to unmount storage. "+err.Error())
-			}
+func formatStrings(values []types.String) []string {
+	var res []string
+	for _, value := range values {
+		res = append(res, value.ValueString())
+	}
+	return res
+}
 
-			restartRequired = true
-		}
+var checksConfigVars = map[string]string{
+	"attempts": "DOKKU_CHECKS_ATTEMPTS",
+	"timeout":  "DOKKU_CHECKS_TIMEOUT",
+	"wait":     "DOKKU_CHECKS_WAIT",
+}
+
+func checksConfigValues(checks *checkModel) map[string]types.Int64 {
+	if checks == nil {
+		checks = &checkModel{}
 	}
-	for planName, planStorage := range plan.Storage {
-		found := false
-		for existingName := range state.Storage {
-			if existingName == planName {
-				found = true
-				break
-			}
-		}
-		if !found {
-			err := r.client.StorageEnsure(ctx, planName, planStorage.LocalDirectory.ValueStringPointer())
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(planName), "Unable to ensure storage", "Unable to ensure storage. "+err.Error())
-			}
+	return map[string]types.Int64{
+		"attempts": checks.Attempts,
+		"timeout":  checks.Timeout,
+		"wait":     checks.Wait,
+	}
+}
 
-			err = r.client.StorageMount(ctx, appName, planName, planStorage.MountPath.ValueString())
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(planName), "Unable to mount storage", "Unable to mount storage. "+err.Error())
-			}
+// readChecks returns checks setup. config is exported config of app, nil if it can't be read
+func (r *appResource) readChecks(ctx context.Context, appName string, state *checkModel, config map[string]string) (*checkModel, error) {
+	disabled, skipped, err := r.client.ChecksProcesses(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
 
-			restartRequired = true
-		}
+	status := "enabled"
+	if len(disabled) == 1 && disabled[0] == "_all_" {
+		status = "disabled"
+		disabled = nil
+	}
+	if len(skipped) == 1 && skipped[0] == "_all_" {
+		status = "skipped"
+		skipped = nil
 	}
-	// --
 
-	// -- checks
-	stateCheckStatus := "enabled"
-	if state.Checks != nil {
-		stateCheckStatus = state.Checks.Status.ValueString()
+	checks := &checkModel{
+		Status:       basetypes.NewStringNull(),
+		WaitToRetire: basetypes.NewInt64Null(),
+		Attempts:     basetypes.NewInt64Null(),
+		Timeout:      basetypes.NewInt64Null(),
+		Wait:         basetypes.NewInt64Null(),
+	}
+	// enabled is default status, keep it only if it is set explicitly
+	if status != "enabled" || (state != nil && !state.Status.IsNull()) {
+		checks.Status = basetypes.NewStringValue(status)
 	}
-	planCheckStatus := "enabled"
-	if plan.Checks != nil {
-		planCheckStatus = plan.Checks.Status.ValueString()
+	for _, processType := range disabled {
+		checks.Disabled = append(checks.Disabled, basetypes.NewStringValue(processType))
 	}
-	if stateCheckStatus != planCheckStatus {
-		err := r.client.ChecksSet(ctx, appName, planCheckStatus)
+	for _, processType := range skipped {
+		checks.Skipped = append(checks.Skipped, basetypes.NewStringValue(processType))
+	}
+
+	if state != nil {
+		values, err := r.readAppProperties(ctx, appName, []appProperty{
+			{"checks", "wait-to-retire", "wait_to_retire", formatInt64(state.WaitToRetire)},
+		})
 		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("checks"), "Unable to set checks", "Unable to set checks. "+err.Error())
+			return nil, err
 		}
+		checks.WaitToRetire = parseInt64(values["wait_to_retire"])
 	}
-	// --
 
-	// -- ports
-	if len(plan.ProxyPorts) > 0 {
-		resp.Diagnostics.AddAttributeWarning(path.Root("proxy_ports"), "proxy_ports attribute is deprecated, use porst attribute instead", "proxy_ports attribute is deprecated, use porst attribute instead")
-	}
-	needToSetPorts := false
-	var portsToSet []dokkuclient.Port
-	for existingHostPort, existingPort := range state.Ports {
-		found := false
-		for planHostPort, planPort := range plan.Ports {
-			if planHostPort == existingHostPort {
-				if planPort.Scheme.Equal(existingPort.Scheme) && planPort.ContainerPort.Equal(existingPort.ContainerPort) {
-					found = true
-				}
-				break
-			}
-		}
-		for planHostPort, planPort := range plan.ProxyPorts {
-			if planHostPort == existingHostPort {
-				if planPort.Scheme.Equal(existingPort.Scheme) && planPort.ContainerPort.Equal(existingPort.ContainerPort) {
-					found = true
-				}
-				break
-			}
-		}
-		if !found {
-			needToSetPorts = true
-		}
+	// only values known in state are read, values set outside of terraform are not managed
+	if state != nil {
+		checks.Attempts = readChecksConfigValue(config, "attempts", state.Attempts)
+		checks.Timeout = readChecksConfigValue(config, "timeout", state.Timeout)
+		checks.Wait = readChecksConfigValue(config, "wait", state.Wait)
 	}
-	for planHostPort, planPort := range plan.Ports {
-		found := false
-		for existingHostPort, existingPort := range state.Ports {
-			if planHostPort == existingHostPort {
-				if planPort.Scheme.Equal(existingPort.Scheme) && planPort.ContainerPort.Equal(existingPort.ContainerPort) {
-					found = true
-				}
-				break
-			}
-		}
-		if !found {
-			needToSetPorts = true
-		}
-		portsToSet = append(portsToSet, dokkuclient.Port{
-			Scheme:        planPort.Scheme.ValueString(),
-			HostPort:      planHostPort,
-			ContainerPort: planPort.ContainerPort.ValueString(),
-		})
+
+	if checks.Status.IsNull() && len(checks.Disabled) == 0 && len(checks.Skipped) == 0 &&
+		checks.WaitToRetire.IsNull() && checks.Attempts.IsNull() && checks.Timeout.IsNull() && checks.Wait.IsNull() {
+		return nil, nil
 	}
-	for planHostPort, planPort := range plan.ProxyPorts {
-		found := false
-		for existingHostPort, existingPort := range state.Ports {
-			if planHostPort == existingHostPort {
-				if planPort.Scheme.Equal(existingPort.Scheme) && planPort.ContainerPort.Equal(existingPort.ContainerPort) {
-					found = true
-				}
-				break
-			}
-		}
-		if !found {
-			needToSetPorts = true
-		}
-		portsToSet = append(portsToSet, dokkuclient.Port{
-			Scheme:        planPort.Scheme.ValueString(),
-			HostPort:      planHostPort,
-			ContainerPort: planPort.ContainerPort.ValueString(),
-		})
+	return checks, nil
+}
+
+// readChecksConfigValue returns value of checks config var if it is known in state. State value is kept if config is unavailable
+func readChecksConfigValue(config map[string]string, attr string, state types.Int64) types.Int64 {
+	if state.IsNull() || config == nil {
+		return state
 	}
-	if needToSetPorts {
-		if len(portsToSet) == 0 {
-			err := r.client.PortsClear(ctx, appName)
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to clear ports", "Unable to clear ports. "+err.Error())
-			}
+	return parseInt64(basetypes.NewStringValue(config[checksConfigVars[attr]]))
+}
 
-			err = r.client.ProxyDisable(ctx, appName)
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to disable ports", "Unable to disable ports. "+err.Error())
-			}
-		} else {
-			err := r.client.PortsSet(ctx, appName, portsToSet)
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to set ports", "Unable to set ports. "+err.Error())
-			}
+// setChecks applies changed checks setup. state is nil on create
+func (r *appResource) setChecks(ctx context.Context, appName string, plan *checkModel, state *checkModel, diags *diag.Diagnostics) {
+	checksPath := path.Root("checks")
+	if plan == nil {
+		plan = &checkModel{}
+	}
+	if state == nil {
+		state = &checkModel{}
+	}
 
-			err = r.client.ProxyEnable(ctx, appName)
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("ports"), "Unable to enable ports", "Unable to enable ports. "+err.Error())
-			}
+	planStatus := plan.Status.ValueString()
+	if planStatus == "" {
+		planStatus = "enabled"
+	}
+	stateStatus := state.Status.ValueString()
+	if stateStatus == "" {
+		stateStatus = "enabled"
+	}
+	stateDisabled := formatStrings(state.Disabled)
+	stateSkipped := formatStrings(state.Skipped)
+	if planStatus != stateStatus {
+		err := r.client.ChecksSet(ctx, appName, planStatus)
+		if err != nil {
+			diags.AddAttributeError(checksPath.AtName("status"), "Unable to set checks", "Unable to set checks. "+err.Error())
 		}
+		// status for all process types resets lists of process types
+		stateDisabled = nil
+		stateSkipped = nil
 	}
-	// --
 
-	// -- domains
-	needToSetDomains := false
-	var domainsToSet []string
-	for _, existingDomain := range state.Domains {
-		found := false
-		for _, planDomain := range plan.Domains {
-			if planDomain == existingDomain {
-				found = true
-				break
-			}
-		}
-		if !found {
-			needToSetDomains = true
+	planDisabled := formatStrings(plan.Disabled)
+	planSkipped := formatStrings(plan.Skipped)
+	var toEnable, toDisable, toSkip []string
+	for _, processType := range append(stateDisabled, stateSkipped...) {
+		if !containsString(planDisabled, processType) && !containsString(planSkipped, processType) {
+			toEnable = append(toEnable, processType)
 		}
 	}
-	for _, planDomain := range plan.Domains {
-		found := false
-		for _, existingDomain := range state.Domains {
-			if planDomain == existingDomain {
-				found = true
-				break
-			}
+	for _, processType := range planDisabled {
+		if !containsString(stateDisabled, processType) {
+			toDisable = append(toDisable, processType)
 		}
-		if !found {
-			needToSetDomains = true
+	}
+	for _, processType := range planSkipped {
+		if !containsString(stateSkipped, processType) {
+			toSkip = append(toSkip, processType)
 		}
-		domainsToSet = append(domainsToSet, planDomain.ValueString())
 	}
-	if needToSetDomains {
-		var err error
-		if len(domainsToSet) == 0 {
-			err = r.client.DomainsDisable(ctx, appName)
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to disable domains support", "Unable to disable domains support. "+err.Error())
-			}
-
-			err = r.client.DomainsClear(ctx, appName)
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to clear domains", "Unable to clear domains. "+err.Error())
-			}
-		} else {
-			err = r.client.DomainsEnable(ctx, appName)
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to enable domains support", "Unable to enable domains support. "+err.Error())
-			}
-
-			err = r.client.DomainsSet(ctx, appName, domainsToSet)
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("domains"), "Unable to set domains", "Unable to set domains. "+err.Error())
-			}
+	for status, processTypes := range map[string][]string{"enabled": toEnable, "disabled": toDisable, "skipped": toSkip} {
+		if len(processTypes) == 0 {
+			continue
+		}
+		err := r.client.ChecksSetProcesses(ctx, appName, status, processTypes)
+		if err != nil {
+			diags.AddAttributeError(checksPath, "Unable to set checks for process types", "Unable to set checks for process types. "+err.Error())
 		}
 	}
-	// TODO run letsencrypt:enable again after adding new domains
-	// --
-
-	// -- docker options
-	for existingValue, existingDockerOption := range state.DockerOptions {
-		found := false
-		for planValue, planDockerOption := range plan.DockerOptions {
-			if existingValue == planValue {
-				found = true
-
-				if !existingDockerOption.Phase.Equal(planDockerOption.Phase) {
-					err := r.client.DockerOptionRemove(ctx, appName, formatDockerOptionsPhases(existingDockerOption.Phase), existingValue)
-					if err != nil {
-						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingValue), "Unable to remove docker option", "Unable to remove docker option. "+err.Error())
-					}
-
-					err = r.client.DockerOptionAdd(ctx, appName, formatDockerOptionsPhases(planDockerOption.Phase), planValue)
-					if err != nil {
-						resp.Diagnostics.AddAttributeError(path.Root("storage").AtMapKey(existingValue), "Unable to add docker option", "Unable to add docker option. "+err.Error())
-					}
 
-					restartRequired = true
-				}
+	r.setAppProperties(ctx, appName, checksPath,
+		[]appProperty{{"checks", "wait-to-retire", "wait_to_retire", formatInt64(plan.WaitToRetire)}},
+		[]appProperty{{"checks", "wait-to-retire", "wait_to_retire", formatInt64(state.WaitToRetire)}},
+		diags,
+	)
 
-				break
-			}
+	stateConfig := checksConfigValues(state)
+	configToSet := make(map[string]string)
+	var configToUnset []string
+	for attr, value := range checksConfigValues(plan) {
+		if value.Equal(stateConfig[attr]) {
+			continue
 		}
-		if !found {
-			err := r.client.DockerOptionRemove(ctx, appName, formatDockerOptionsPhases(existingDockerOption.Phase), existingValue)
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("docker_options").AtMapKey(existingValue), "Unable to remove docker option", "Unable to remove docker option. "+err.Error())
-			}
-
-			restartRequired = true
+		if value.IsNull() {
+			configToUnset = append(configToUnset, checksConfigVars[attr])
+		} else {
+			configToSet[checksConfigVars[attr]] = formatInt64(value).ValueString()
 		}
 	}
-	for planValue, planDockerOption := range plan.DockerOptions {
-		found := false
-		for existingValue := range state.DockerOptions {
-			if existingValue == planValue {
-				found = true
-				break
-			}
+	if len(configToUnset) != 0 {
+		err := r.client.ConfigUnset(ctx, appName, configToUnset)
+		if err != nil {
+			diags.AddAttributeError(checksPath, "Unable to unset checks config", "Unable to unset checks config. "+err.Error())
 		}
-		if !found {
-			err := r.client.DockerOptionAdd(ctx, appName, formatDockerOptionsPhases(planDockerOption.Phase), planValue)
-			if err != nil {
-				resp.Diagnostics.AddAttributeError(path.Root("docker_options").AtMapKey(planValue), "Unable to add docker option", "Unable to add docker option. "+err.Error())
-			}
+	}
+	if len(configToSet) != 0 {
+		err := r.client.ConfigSet(ctx, appName, configToSet, nil)
+		if err != nil {
+			diags.AddAttributeError(checksPath, "Unable to set checks config", "Unable to set checks config. "+err.Error())
+		}
+	}
+}
 
-			restartRequired = true
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
 	}
-	// --
+	return false
+}
 
-	// -- networks
-	if state.Networks != nil {
-		if plan.Networks != nil {
-			if !plan.Networks.AttachPostCreate.Equal(state.Networks.AttachPostCreate) {
-				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "attach-post-create", plan.Networks.AttachPostCreate.ValueString())
-				if err != nil {
-					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_create"), "Unable to set network", "Unable to set network. "+err.Error())
-				}
-			}
-			if !plan.Networks.AttachPostDeploy.Equal(state.Networks.AttachPostDeploy) {
-				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "attach-post-deploy", plan.Networks.AttachPostDeploy.ValueString())
-				if err != nil {
-					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_deploy"), "Unable to set network", "Unable to set network. "+err.Error())
-				}
-			}
-			if !plan.Networks.InitialNetwork.Equal(state.Networks.InitialNetwork) {
-				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "initial-network", plan.Networks.InitialNetwork.ValueString())
-				if err != nil {
-					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("initial_network"), "Unable to set network", "Unable to set network. "+err.Error())
-				}
-			}
-		} else {
-			if !state.Networks.AttachPostCreate.IsNull() {
-				err := r.client.NetworkUnsetForApp(ctx, appName, "attach-post-create")
-				if err != nil {
-					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_create"), "Unable to unset network", "Unable to unset network. "+err.Error())
-				}
-			}
-			if !state.Networks.AttachPostDeploy.IsNull() {
-				err := r.client.NetworkUnsetForApp(ctx, appName, "attach-post-deploy")
-				if err != nil {
-					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_deploy"), "Unable to unset network", "Unable to unset network. "+err.Error())
-				}
-			}
-			if !state.Networks.InitialNetwork.IsNull() {
-				err := r.client.NetworkUnsetForApp(ctx, appName, "initial-network")
-				if err != nil {
-					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("initial_network"), "Unable to unset network", "Unable to unset network. "+err.Error())
-				}
-			}
+// appProperty is app property of dokku plugin, which is set by "<plugin>:set <app> <key> <value>"
+type appProperty struct {
+	plugin string
+	key    string
+	attr   string
+	value  types.String
+}
+
+// buildProperties returns properties of builder plugins with values from model
+func buildProperties(build *buildModel) []appProperty {
+	if build == nil {
+		build = &buildModel{}
+	}
+	return []appProperty{
+		{"builder", "selected", "builder", build.Builder},
+		{"builder", "build-dir", "build_dir", build.BuildDir},
+		{"builder-dockerfile", "dockerfile-path", "dockerfile_path", build.DockerfilePath},
+		{"builder-pack", "projecttoml-path", "projecttoml_path", build.ProjecttomlPath},
+		{"builder-nixpacks", "nixpackstoml-path", "nixpackstoml_path", build.NixpackstomlPath},
+		{"builder-nixpacks", "no-cache", "nixpacks_no_cache", formatBool(build.NixpacksNoCache)},
+	}
+}
+
+func (r *appResource) readBuild(ctx context.Context, appName string, state *buildModel) (*buildModel, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	values, err := r.readAppProperties(ctx, appName, buildProperties(state))
+	if err != nil {
+		return nil, err
+	}
+
+	build := &buildModel{
+		Builder:          values["builder"],
+		BuildDir:         values["build_dir"],
+		DockerfilePath:   values["dockerfile_path"],
+		ProjecttomlPath:  values["projecttoml_path"],
+		NixpackstomlPath: values["nixpackstoml_path"],
+		NixpacksNoCache:  parseBool(values["nixpacks_no_cache"]),
+	}
+	if *build == (buildModel{}) {
+		return nil, nil
+	}
+	return build, nil
+}
+
+// readAppProperties returns current values of properties by attribute name. Only properties set in state are read,
+// others are kept null to not show defaults of dokku as drift
+func (r *appResource) readAppProperties(ctx context.Context, appName string, properties []appProperty) (map[string]types.String, error) {
+	reports := make(map[string]map[string]string)
+	values := make(map[string]types.String)
+	for _, property := range properties {
+		values[property.attr] = basetypes.NewStringNull()
+		if property.value.IsNull() {
+			continue
 		}
-	} else {
-		if plan.Networks != nil {
-			if !plan.Networks.AttachPostCreate.IsNull() {
-				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "attach-post-create", plan.Networks.AttachPostCreate.ValueString())
-				if err != nil {
-					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_create"), "Unable to set network", "Unable to set network. "+err.Error())
-				}
-			}
-			if !plan.Networks.AttachPostDeploy.IsNull() {
-				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "attach-post-deploy", plan.Networks.AttachPostDeploy.ValueString())
-				if err != nil {
-					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("attach_post_deploy"), "Unable to set network", "Unable to set network. "+err.Error())
-				}
-			}
-			if !plan.Networks.InitialNetwork.IsNull() {
-				err := r.client.NetworkEnsureAndSetForApp(ctx, appName, "initial-network", plan.Networks.InitialNetwork.ValueString())
-				if err != nil {
-					resp.Diagnostics.AddAttributeError(path.Root("networks").AtName("initial_network"), "Unable to set network", "Unable to set network. "+err.Error())
-				}
+
+		if _, ok := reports[property.plugin]; !ok {
+			report, err := r.client.AppPropertiesReport(ctx, property.plugin, appName)
+			if err != nil {
+				return nil, err
 			}
+			reports[property.plugin] = report
 		}
-	}
-	// --
 
-	// -- deploy
-	if plan.Deploy != nil {
-		deployed, err := r.deploy(ctx, plan.AppName.ValueString(), *plan.Deploy)
-		if err != nil {
-			resp.Diagnostics.AddAttributeError(path.Root("deploy"), "Unable to deploy", "Unable to deploy. "+err.Error())
-		}
-		if deployed {
-			restartRequired = false
+		value := reports[property.plugin][strings.ReplaceAll(property.key, "-", " ")]
+		if value != "" {
+			values[property.attr] = basetypes.NewStringValue(value)
 		}
 	}
-	// --
+	return values, nil
+}
 
-	if !resp.Diagnostics.HasError() && restartRequired {
-		err := r.client.ProcessRestart(ctx, appName)
+// setAppProperties sets properties changed in plan. state is nil on create
+func (r *appResource) setAppProperties(ctx context.Context, appName string, attrPath path.Path, plan []appProperty, state []appProperty, diags *diag.Diagnostics) {
+	for i, property := range plan {
+		if state != nil && property.value.Equal(state[i].value) {
+			continue
+		}
+		if state == nil && property.value.IsNull() {
+			continue
+		}
+		err := r.client.AppPropertySet(ctx, property.plugin, appName, property.key, property.value.ValueString())
 		if err != nil {
-			resp.Diagnostics.AddError("Unable to restart process", "Unable to restart process. "+err.Error())
+			diags.AddAttributeError(attrPath.AtName(property.attr), "Unable to set property", "Unable to set property. "+err.Error())
 		}
 	}
-	if resp.Diagnostics.HasError() {
-		return
+}
+
+func formatBool(value types.Bool) types.String {
+	if value.IsNull() || value.IsUnknown() {
+		return basetypes.NewStringNull()
 	}
+	return basetypes.NewStringValue(strconv.FormatBool(value.ValueBool()))
+}
 
-	diags = resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+func parseBool(value types.String) types.Bool {
+	b, err := strconv.ParseBool(value.ValueString())
+	if err != nil {
+		return basetypes.NewBoolNull()
 	}
+	return basetypes.NewBoolValue(b)
 }
 
-// Delete deletes the resource and removes the Terraform state on success.
-func (r *appResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	// Retrieve values from state
-	var state appResourceModel
-	diags := req.State.Get(ctx, &state)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+func formatInt64(value types.Int64) types.String {
+	if value.IsNull() || value.IsUnknown() {
+		return basetypes.NewStringNull()
 	}
+	return basetypes.NewStringValue(strconv.FormatInt(value.ValueInt64(), 10))
+}
 
-	exists, err := r.client.AppExists(ctx, state.AppName.ValueString())
+func parseInt64(value types.String) types.Int64 {
+	i, err := strconv.ParseInt(value.ValueString(), 10, 64)
 	if err != nil {
-		resp.Diagnostics.AddAttributeError(path.Root("app_name"), "Unable to check app existence", "Unable to check app existence. "+err.Error())
-		return
+		return basetypes.NewInt64Null()
 	}
-	if !exists {
-		return
+	return basetypes.NewInt64Value(i)
+}
+
+func resourceValuesSchemaAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:    true,
+		Description: description,
+		Attributes: map[string]schema.Attribute{
+			"cpu": schema.StringAttribute{
+				Optional:    true,
+				Description: "CPU count",
+			},
+			"memory": schema.StringAttribute{
+				Optional:    true,
+				Description: "Memory in megabytes or with unit (b, k, m, g)",
+			},
+			"memory_swap": schema.StringAttribute{
+				Optional:    true,
+				Description: "Memory swap in megabytes or with unit (b, k, m, g)",
+			},
+			"nvidia_gpu": schema.StringAttribute{
+				Optional:    true,
+				Description: "Count of Nvidia GPUs",
+			},
+		},
 	}
+}
 
-	// Delete existing app
-	err = r.client.AppDestroy(ctx, state.AppName.ValueString())
+func formatResourceValues(values *resourceValuesModel) map[string]string {
+	res := make(map[string]string)
+	if values == nil {
+		return res
+	}
+	if !values.Cpu.IsNull() {
+		res["cpu"] = values.Cpu.ValueString()
+	}
+	if !values.Memory.IsNull() {
+		res["memory"] = values.Memory.ValueString()
+	}
+	if !values.MemorySwap.IsNull() {
+		res["memory-swap"] = values.MemorySwap.ValueString()
+	}
+	if !values.NvidiaGpu.IsNull() {
+		res["nvidia-gpu"] = values.NvidiaGpu.ValueString()
+	}
+	return res
+}
+
+func parseResourceValues(values map[string]string) *resourceValuesModel {
+	res := &resourceValuesModel{
+		Cpu:        basetypes.NewStringNull(),
+		Memory:     basetypes.NewStringNull(),
+		MemorySwap: basetypes.NewStringNull(),
+		NvidiaGpu:  basetypes.NewStringNull(),
+	}
+	if v, ok := values["cpu"]; ok {
+		res.Cpu = basetypes.NewStringValue(v)
+	}
+	if v, ok := values["memory"]; ok {
+		res.Memory = basetypes.NewStringValue(v)
+	}
+	if v, ok := values["memory-swap"]; ok {
+		res.MemorySwap = basetypes.NewStringValue(v)
+	}
+	if v, ok := values["nvidia-gpu"]; ok {
+		res.NvidiaGpu = basetypes.NewStringValue(v)
+	}
+	if res.Cpu.IsNull() && res.Memory.IsNull() && res.MemorySwap.IsNull() && res.NvidiaGpu.IsNull() {
+		return nil
+	}
+	return res
+}
+
+func resourceValuesEqual(a *resourceValuesModel, b *resourceValuesModel) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatScale(scale map[string]types.Int64) map[string]int64 {
+	res := make(map[string]int64)
+	for processType, count := range scale {
+		res[processType] = count.ValueInt64()
+	}
+	return res
+}
+
+// lockApp locks app and returns function to unlock it. Error is added to diagnostics if app is already locked.
+// Unlock is done once, for the name appName points to at the time of the call, so the app may be renamed while locked
+func (r *appResource) lockApp(ctx context.Context, appName *string, diags *diag.Diagnostics) (unlock func()) {
+	locked, err := r.client.AppLocked(ctx, *appName)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to destroy app", "Unable to destroy app. "+err.Error())
+		diags.AddAttributeError(path.Root("lock_during_apply"), "Unable to check app lock", "Unable to check app lock. "+err.Error())
+		return func() {}
+	}
+	if locked {
+		diags.AddAttributeError(path.Root("lock_during_apply"), "App is locked", "App is locked. Deploy is in progress or app is locked with apps:lock")
+		return func() {}
+	}
+
+	err = r.client.AppLock(ctx, *appName)
+	if err != nil {
+		diags.AddAttributeError(path.Root("lock_during_apply"), "Unable to lock app", "Unable to lock app. "+err.Error())
+		return func() {}
+	}
+
+	unlocked := false
+	return func() {
+		if unlocked {
+			return
+		}
+		unlocked = true
+
+		// app is destroyed if create fails
+		exists, err := r.client.AppExists(ctx, *appName)
+		if err == nil && !exists {
+			return
+		}
+		err = r.client.AppUnlock(ctx, *appName)
+		if err != nil {
+			diags.AddAttributeError(path.Root("lock_during_apply"), "Unable to unlock app", "Unable to unlock app. "+err.Error())
+		}
+	}
+}
+
+// relockApp locks renamed app again if lock was not moved with app directory
+func (r *appResource) relockApp(ctx context.Context, appName string, diags *diag.Diagnostics) {
+	locked, err := r.client.AppLocked(ctx, appName)
+	if err != nil {
+		diags.AddAttributeError(path.Root("lock_during_apply"), "Unable to check app lock", "Unable to check app lock. "+err.Error())
 		return
 	}
+	if locked {
+		return
+	}
+	err = r.client.AppLock(ctx, appName)
+	if err != nil {
+		diags.AddAttributeError(path.Root("lock_during_apply"), "Unable to lock app", "Unable to lock app. "+err.Error())
+	}
 }
 
-func (r *appResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to app_name attribute
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app_name"), req.ID)...)
+// isRunning returns running state of app. Not deployed app is never running
+func (r *appResource) isRunning(ctx context.Context, appName string) (running bool, deployed bool, err error) {
+	report, err := r.client.ProcessReport(ctx, appName)
+	if err != nil {
+		return false, false, err
+	}
+	deployed = report["Deployed"] == "true"
+	return deployed && report["Running"] != "false", deployed, nil
 }
 
-func (r *appResource) deploy(ctx context.Context, appName string, deployModel deployModel) (deployed bool, err error) {
-	switch deployModel.Type.ValueString() {
-	case "archive":
-		err = r.client.DeployFromArchive(ctx, appName, deployModel.ArchiveType.ValueString(), deployModel.ArchiveUrl.ValueString())
-		deployed = err == nil
-	case "docker_image":
-		if !deployModel.Login.IsNull() && !deployModel.Password.IsNull() {
-			u, err := url.Parse("https://" + deployModel.DockerImage.ValueString())
-			if err != nil {
-				return false, fmt.Errorf("unable to parse url: %w", err)
-			}
-			err = r.client.RegistryLogin(ctx, u.Host, deployModel.Login.ValueString(), deployModel.Password.ValueString())
-			if err != nil {
-				return false, fmt.Errorf("unable to login to registry: %w", err)
+// isDokkuManagedConfigKey returns true for config keys set by dokku. Default and configured rev env vars of all provided
+// git setups are treated as managed. Vars set by service links (e.g. DATABASE_URL) are found in config by the same
+// value as DOKKU_<SERVICE>_<NAME>_URL var, which is set by link too
+func isDokkuManagedConfigKey(key string, config map[string]string, gits ...*gitModel) bool {
+	if strings.HasPrefix(key, "DOKKU_") || key == "GIT_REV" || key == "NO_VHOST" {
+		return true
+	}
+	if strings.HasSuffix(key, "_URL") && config[key] != "" {
+		for k, v := range config {
+			if strings.HasPrefix(k, "DOKKU_") && strings.HasSuffix(k, "_URL") && v == config[key] {
+				return true
 			}
 		}
-
-		deployed, err = r.client.DeployFromImage(ctx, appName, deployModel.DockerImage.ValueString(), deployModel.AllowRebuild.ValueBool())
-	case "git_repository":
-		if !deployModel.Login.IsNull() && !deployModel.Password.IsNull() {
-			u, err := url.Parse(deployModel.GitRepository.ValueString())
-			if err != nil {
-				return false, fmt.Errorf("unable to parse url: %w", err)
-			}
-			err = r.client.GitAuth(ctx, u.Host, deployModel.Login.ValueString(), deployModel.Password.ValueString())
-			if err != nil {
-				return false, fmt.Errorf("unable to login to git: %w", err)
-			}
+	}
+	for _, git := range gits {
+		if git != nil && key == git.RevEnvVar.ValueString() {
+			return true
 		}
+	}
+	return false
+}
 
-		err = r.client.DeploySyncRepository(ctx, appName, deployModel.GitRepository.ValueString(), deployModel.GitRepositoryRef.ValueString())
-		deployed = err == nil
-	default:
-		err = fmt.Errorf("Unknown deploy type %s", deployModel.Type.ValueString())
+// gitRevEnvVar returns config key set to deployed git revision
+func gitRevEnvVar(git *gitModel) string {
+	if git == nil || git.RevEnvVar.IsNull() {
+		return "GIT_REV"
 	}
-	return
+	return git.RevEnvVar.ValueString()
+}
+
+func isGitSha(ref string) bool {
+	return regexp.MustCompile(`^[0-9a-f]{7,40}$`).MatchString(ref)
+}
+
+func formatStorageMountOptions(storage storageModel) (options []string) {
+	if storage.ReadOnly.ValueBool() {
+		options = append(options, "ro")
+	}
+	var extraOptions []string
+	for _, option := range storage.MountOptions.Elements() {
+		//nolint:forcetypeassert
+		optionStr := option.(types.String)
+		extraOptions = append(extraOptions, optionStr.ValueString())
+	}
+	sort.Strings(extraOptions)
+	return append(options, extraOptions...)
 }
 
 func formatDockerOptionsPhases(phasesSet types.Set) (phases []string) {
@@ -0,0 +1,251 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	dokkuclient "terraform-provider-dokku/internal/provider/dokku_client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+var (
+	_ resource.Resource                = &certificateResource{}
+	_ resource.ResourceWithConfigure   = &certificateResource{}
+	_ resource.ResourceWithImportState = &certificateResource{}
+)
+
+func NewCertificateResource() resource.Resource {
+	return &certificateResource{}
+}
+
+type certificateResource struct {
+	client *dokkuclient.Client
+}
+
+type certificateResourceModel struct {
+	AppName     types.String   `tfsdk:"app_name"`
+	Certificate types.String   `tfsdk:"certificate"`
+	PrivateKey  types.String   `tfsdk:"private_key"`
+	ExpiresAt   types.String   `tfsdk:"expires_at"`
+	Subject     types.String   `tfsdk:"subject"`
+	Hostnames   []types.String `tfsdk:"hostnames"`
+}
+
+// Metadata returns the resource type name.
+func (r *certificateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certificate"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *certificateResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	//nolint:forcetypeassert
+	r.client = req.ProviderData.(*dokkuclient.Client)
+}
+
+// Schema defines the schema for the resource.
+func (r *certificateResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"app_name": schema.StringAttribute{
+				Required:    true,
+				Description: "App name to add certificate to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z][a-z0-9-]*$`), "invalid app_name"),
+				},
+			},
+			"certificate": schema.StringAttribute{
+				Required:    true,
+				Description: "Certificate in PEM format. Can include intermediate certificates",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`-----BEGIN CERTIFICATE-----`), "must be in PEM format"),
+				},
+			},
+			"private_key": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				Description: "Private key in PEM format",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`), "must be in PEM format"),
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Expiration date of certificate",
+			},
+			"subject": schema.StringAttribute{
+				Computed:    true,
+				Description: "Subject of certificate",
+			},
+			"hostnames": schema.ListAttribute{
+				Computed:    true,
+				Description: "Hostnames (SANs) of certificate",
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *certificateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state certificateResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	exists, err := r.readCertificate(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read certificate", "Unable to read certificate. "+err.Error())
+		return
+	}
+	if !exists {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *certificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan certificateResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.CertsAdd(ctx, plan.AppName.ValueString(), plan.Certificate.ValueString(), plan.PrivateKey.ValueString(), false)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to add certificate", "Unable to add certificate. "+err.Error())
+		return
+	}
+
+	_, err = r.readCertificate(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read certificate", "Unable to read certificate. "+err.Error())
+		return
+	}
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *certificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan certificateResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var state certificateResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.AppName.ValueString() != state.AppName.ValueString() {
+		resp.Diagnostics.AddAttributeError(path.Root("app_name"), "App name can't be changed", "App name can't be changed")
+		return
+	}
+
+	err := r.client.CertsAdd(ctx, plan.AppName.ValueString(), plan.Certificate.ValueString(), plan.PrivateKey.ValueString(), true)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to update certificate", "Unable to update certificate. "+err.Error())
+		return
+	}
+
+	_, err = r.readCertificate(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read certificate", "Unable to read certificate. "+err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *certificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state certificateResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	report, err := r.client.CertsReport(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read certificate", "Unable to read certificate. "+err.Error())
+		return
+	}
+	if report["enabled"] != "true" {
+		return
+	}
+
+	err = r.client.CertsRemove(ctx, state.AppName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to remove certificate", "Unable to remove certificate. "+err.Error())
+		return
+	}
+}
+
+func (r *certificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to app_name attribute
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app_name"), req.ID)...)
+}
+
+// readCertificate sets computed attributes from certs:report. Returns false if app has no certificate
+func (r *certificateResource) readCertificate(ctx context.Context, model *certificateResourceModel) (bool, error) {
+	report, err := r.client.CertsReport(ctx, model.AppName.ValueString())
+	if err != nil {
+		return false, err
+	}
+	if report["enabled"] != "true" {
+		return false, nil
+	}
+
+	model.ExpiresAt = basetypes.NewStringValue(report["expires at"])
+	model.Subject = basetypes.NewStringValue(strings.TrimSpace(strings.TrimPrefix(report["subject"], "subject=")))
+	model.Hostnames = []types.String{}
+	for _, hostname := range strings.Fields(report["hostnames"]) {
+		model.Hostnames = append(model.Hostnames, basetypes.NewStringValue(hostname))
+	}
+	return true, nil
+}
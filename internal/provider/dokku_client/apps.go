@@ -22,7 +22,66 @@ func (c *Client) AppExists(ctx context.Context, appName string) (bool, error) {
 	return true, nil
 }
 
+// AppRename renames app keeping its config, storage and links. App is rebuilt with new name if it was deployed
+func (c *Client) AppRename(ctx context.Context, oldAppName string, newAppName string) error {
+	_, _, err := c.Run(ctx, fmt.Sprintf("apps:rename %s %s", oldAppName, newAppName))
+	return err
+}
+
+// AppClone creates app as copy of existing one. Copy is deployed unless skipDeploy is true
+func (c *Client) AppClone(ctx context.Context, sourceAppName string, appName string, skipDeploy bool) error {
+	flags := ""
+	if skipDeploy {
+		flags = "--skip-deploy "
+	}
+	_, _, err := c.Run(ctx, fmt.Sprintf("apps:clone %s%s %s", flags, sourceAppName, appName))
+	return err
+}
+
+// AppLock locks app to prevent deploys (apps:lock)
+func (c *Client) AppLock(ctx context.Context, appName string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("apps:lock %s", appName))
+	return err
+}
+
+func (c *Client) AppUnlock(ctx context.Context, appName string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("apps:unlock %s", appName))
+	return err
+}
+
+func (c *Client) AppLocked(ctx context.Context, appName string) (bool, error) {
+	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("apps:locked %s", appName))
+	if err != nil {
+		if strings.Contains(stdout, "Deploy lock does not exist") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (c *Client) AppDestroy(ctx context.Context, appName string) error {
 	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("apps:destroy %s --force", appName))
 	return err
 }
+
+func (c *Client) AppsList(ctx context.Context) ([]string, error) {
+	stdout, _, err := c.RunQuiet(ctx, "apps:list")
+	if err != nil {
+		if strings.Contains(stdout, "You haven't deployed any applications yet") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var res []string
+	lines := strings.Split(stdout, "\n")
+	for _, line := range lines {
+		appName := strings.TrimSpace(line)
+		if appName == "" || strings.HasPrefix(appName, "=====>") {
+			continue
+		}
+		res = append(res, appName)
+	}
+	return res, nil
+}
@@ -0,0 +1,39 @@
+package dokkuclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+func (c *Client) BuildpacksList(ctx context.Context, appName string) ([]string, error) {
+	report, err := c.AppPropertiesReport(ctx, "buildpacks", appName)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, buildpack := range strings.Split(report["list"], ",") {
+		buildpack = strings.TrimSpace(buildpack)
+		if buildpack != "" {
+			res = append(res, buildpack)
+		}
+	}
+	return res, nil
+}
+
+// BuildpacksSet replaces all buildpacks of app keeping their order
+func (c *Client) BuildpacksSet(ctx context.Context, appName string, buildpacks []string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("buildpacks:clear %s", appName))
+	if err != nil {
+		return err
+	}
+
+	for _, buildpack := range buildpacks {
+		_, _, err = c.RunQuiet(ctx, fmt.Sprintf("buildpacks:add %s %s", appName, buildpack))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package dokkuclient
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// CertsReport returns ssl report of app. Keys are titles without "Ssl " prefix
+func (c *Client) CertsReport(ctx context.Context, appName string) (map[string]string, error) {
+	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("certs:report %s", appName))
+	if err != nil {
+		return nil, err
+	}
+	return parseReport(stdout, "Ssl "), nil
+}
+
+// CertsAdd adds (or replaces if update is true) certificate and private key of app
+func (c *Client) CertsAdd(ctx context.Context, appName string, certificate string, privateKey string, update bool) error {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	for _, file := range []struct {
+		name    string
+		content string
+	}{
+		{"server.crt", certificate},
+		{"server.key", privateKey},
+	} {
+		err := tw.WriteHeader(&tar.Header{
+			Name: file.name,
+			Mode: 0600,
+			Size: int64(len(file.content)),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to write tar header: %w", err)
+		}
+		_, err = tw.Write([]byte(file.content))
+		if err != nil {
+			return fmt.Errorf("unable to write tar content: %w", err)
+		}
+	}
+	err := tw.Close()
+	if err != nil {
+		return fmt.Errorf("unable to close tar archive: %w", err)
+	}
+
+	action := "add"
+	if update {
+		action = "update"
+	}
+	return c.runWithStdin(ctx, fmt.Sprintf("--quiet certs:%s %s", action, appName), &archive)
+}
+
+func (c *Client) CertsRemove(ctx context.Context, appName string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("certs:remove %s", appName))
+	return err
+}
@@ -7,6 +7,11 @@ import (
 )
 
 func (c *Client) ChecksSet(ctx context.Context, appName string, status string) error {
+	return c.ChecksSetProcesses(ctx, appName, status, nil)
+}
+
+// ChecksSetProcesses enables, disables or skips checks for process types. Empty list means all process types
+func (c *Client) ChecksSetProcesses(ctx context.Context, appName string, status string, processTypes []string) error {
 	var action string
 	switch status {
 	case "enabled":
@@ -19,32 +24,29 @@ func (c *Client) ChecksSet(ctx context.Context, appName string, status string) e
 		return fmt.Errorf("Invalid status value. Valid values are: enabled, disabled, skipped")
 	}
 
-	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("checks:%s %s", action, appName))
+	cmd := fmt.Sprintf("checks:%s %s", action, appName)
+	if len(processTypes) != 0 {
+		cmd = fmt.Sprintf("%s %s", cmd, strings.Join(processTypes, ","))
+	}
+	_, _, err := c.RunQuiet(ctx, cmd)
 	return err
 }
 
-func (c *Client) ChecksGet(ctx context.Context, appName string) (status string, err error) {
-	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("checks:report %s", appName))
+// ChecksProcesses returns process types with disabled and skipped checks. "_all_" is returned for all process types
+func (c *Client) ChecksProcesses(ctx context.Context, appName string) (disabled []string, skipped []string, err error) {
+	report, err := c.AppPropertiesReport(ctx, "checks", appName)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
+	return parseChecksList(report["disabled list"]), parseChecksList(report["skipped list"]), nil
+}
 
-	lines := strings.Split(stdout, "\n")
-	for _, line := range lines {
-		parts := strings.Split(line, ":")
-		title := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		switch title {
-		case "Checks disabled list":
-			if value == "_all_" {
-				return "disabled", nil
-			}
-		case "Checks skipped list":
-			if value == "_all_" {
-				return "skipped", nil
-			}
+func parseChecksList(value string) (res []string) {
+	for _, processType := range strings.Split(value, ",") {
+		processType = strings.TrimSpace(processType)
+		if processType != "" && processType != "none" {
+			res = append(res, processType)
 		}
 	}
-	return "enabled", nil
+	return
 }
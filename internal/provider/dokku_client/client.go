@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
@@ -14,13 +15,15 @@ import (
 	"github.com/melbahja/goph"
 )
 
-func New(client *goph.Client, logSshCommands bool, uploadAppName string, uploadSplitBytes int) *Client {
+func New(client *goph.Client, logSshCommands bool, uploadAppName string, uploadSplitBytes int, uploadImage string, uploadAppPersistent bool) *Client {
 	return &Client{
 		client:         client,
 		logSshCommands: logSshCommands,
 
-		uploadAppName:    uploadAppName,
-		uploadSplitBytes: uploadSplitBytes,
+		uploadAppName:       uploadAppName,
+		uploadSplitBytes:    uploadSplitBytes,
+		uploadImage:         uploadImage,
+		uploadAppPersistent: uploadAppPersistent,
 	}
 }
 
@@ -28,8 +31,13 @@ type Client struct {
 	client         *goph.Client
 	logSshCommands bool
 
-	uploadAppName    string
-	uploadSplitBytes int
+	uploadAppName       string
+	uploadSplitBytes    int
+	uploadImage         string
+	uploadAppPersistent bool
+	uploadMutex         sync.Mutex
+	uploadCleanupOnce   sync.Once
+	uploadAppDeployed   bool
 
 	dokkuVersion semver.Version
 }
@@ -82,6 +90,38 @@ func (c *Client) Run(ctx context.Context, cmd string, sensitiveStrings ...string
 	return
 }
 
+// runWithStdin runs ssh command passing data to its stdin. Data is not logged
+func (c *Client) runWithStdin(ctx context.Context, cmd string, stdin io.Reader) error {
+	// disabling concurrent calls
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if c.logSshCommands {
+		tflog.Error(ctx, "SSH cmd", map[string]any{"cmd": cmd})
+	} else {
+		tflog.Debug(ctx, "SSH cmd", map[string]any{"cmd": cmd})
+	}
+
+	session, err := c.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("unable to open ssh session: %w", err)
+	}
+	defer session.Close()
+
+	var output singleWriter
+	session.Stdin = stdin
+	session.Stdout = &output
+	session.Stderr = &output
+
+	err = session.Run(cmd)
+	if err != nil {
+		stdout := strings.TrimSuffix(output.b.String(), "\n")
+		status := parseStatusCode(err.Error())
+		return fmt.Errorf("Error [%d]: %s", status, stdout)
+	}
+	return nil
+}
+
 func parseStatusCode(str string) int {
 	re := regexp.MustCompile("^Process exited with status ([0-9]+)$")
 	found := re.FindStringSubmatch(str)
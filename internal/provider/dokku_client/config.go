@@ -21,12 +21,19 @@ func (c *Client) ConfigExport(ctx context.Context, appName string) (res map[stri
 	return
 }
 
-func (c *Client) ConfigSet(ctx context.Context, appName string, data map[string]string) error {
+// ConfigSet sets config vars. Values of sensitiveData are not logged
+func (c *Client) ConfigSet(ctx context.Context, appName string, data map[string]string, sensitiveData map[string]string) error {
 	dataStr := ""
 	for k, v := range data {
 		dataStr = fmt.Sprintf("%s %s=%q", dataStr, k, base64.StdEncoding.EncodeToString([]byte(v)))
 	}
-	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("config:set --no-restart --encoded %s %s", appName, dataStr))
+	var sensitiveStrings []string
+	for k, v := range sensitiveData {
+		encoded := base64.StdEncoding.EncodeToString([]byte(v))
+		dataStr = fmt.Sprintf("%s %s=%q", dataStr, k, encoded)
+		sensitiveStrings = append(sensitiveStrings, encoded)
+	}
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("config:set --no-restart --encoded %s %s", appName, dataStr), sensitiveStrings...)
 	return err
 }
 
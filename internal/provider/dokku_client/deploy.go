@@ -43,3 +43,11 @@ func (c *Client) DeploySyncRepository(ctx context.Context, appName string, repos
 	_, _, err := c.Run(ctx, fmt.Sprintf("git:sync --build %s %s %s", appName, repositoryUrl, ref))
 	return err
 }
+
+func (c *Client) GitReport(ctx context.Context, appName string) (map[string]string, error) {
+	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("git:report %s", appName))
+	if err != nil {
+		return nil, err
+	}
+	return parseReport(stdout, "Git "), nil
+}
@@ -3,30 +3,87 @@ package dokkuclient
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
-func (c *Client) DockerOptionExists(ctx context.Context, appName string, phase string, value string) (bool, error) {
+var DockerOptionsPhases = []string{"build", "deploy", "run"}
+
+// DockerOptionsReport returns options for each phase. Every option is normalized using NormalizeDockerOption
+func (c *Client) DockerOptionsReport(ctx context.Context, appName string) (res map[string][]string, err error) {
 	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("docker-options:report %s", appName))
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	expectedName := fmt.Sprintf("Docker options %s", phase)
+	res = make(map[string][]string)
 	lines := strings.Split(stdout, "\n")
 	for _, line := range lines {
 		parts := strings.SplitN(line, ":", 2)
-		name := strings.TrimSpace(parts[0])
-		if expectedName != name {
+		if len(parts) != 2 {
 			continue
 		}
-		existingOptions := strings.TrimSpace(parts[1])
-		if !strings.Contains(existingOptions, value) {
-			return false, nil
+		name := strings.TrimSpace(parts[0])
+		for _, phase := range DockerOptionsPhases {
+			if name == fmt.Sprintf("Docker options %s", phase) {
+				res[phase] = splitDockerOptions(parts[1])
+			}
+		}
+	}
+	return
+}
+
+// dockerBooleanFlags are docker flags without value
+var dockerBooleanFlags = map[string]bool{
+	"-d": true, "--detach": true,
+	"-i": true, "--interactive": true,
+	"-t": true, "--tty": true,
+	"-it": true, "-ti": true,
+	"-P": true, "--publish-all": true,
+	"--init":                  true,
+	"--no-healthcheck":        true,
+	"--oom-kill-disable":      true,
+	"--privileged":            true,
+	"--read-only":             true,
+	"--rm":                    true,
+	"--disable-content-trust": true,
+	"--no-cache":              true,
+	"--quiet":                 true,
+	"-q":                      true,
+}
+
+var dockerFlagRegexp = regexp.MustCompile(`^--?[A-Za-z][A-Za-z0-9-]*(=.*)?$`)
+
+// splitDockerOptions splits options string to separate options.
+// Every option starts with flag and includes all following arguments: "--label a -v /a:/b" -> ["--label a", "-v /a:/b"].
+// Flag without value takes next argument as value even if it starts with dash: "--cpu-shares -1"
+func splitDockerOptions(options string) (res []string) {
+	var current []string
+	for _, token := range strings.Fields(options) {
+		if len(current) != 0 && dockerFlagRegexp.MatchString(token) && !expectsDockerFlagValue(current) {
+			res = append(res, strings.Join(current, " "))
+			current = nil
 		}
-		return true, nil
+		current = append(current, token)
+	}
+	if len(current) != 0 {
+		res = append(res, strings.Join(current, " "))
+	}
+	return
+}
+
+// expectsDockerFlagValue returns true if option consists of single flag, which takes value
+func expectsDockerFlagValue(option []string) bool {
+	if len(option) != 1 {
+		return false
 	}
-	return false, nil
+	flag := option[0]
+	return dockerFlagRegexp.MatchString(flag) && !strings.Contains(flag, "=") && !dockerBooleanFlags[flag]
+}
+
+// NormalizeDockerOption removes extra whitespaces from option
+func NormalizeDockerOption(option string) string {
+	return strings.Join(strings.Fields(option), " ")
 }
 
 func (c *Client) DockerOptionAdd(ctx context.Context, appName string, phases []string, value string) error {
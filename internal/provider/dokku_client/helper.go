@@ -1,7 +1,26 @@
 package dokkuclient
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 func DoubleDashArg[T any](key string, value T) string {
-	return fmt.Sprintf("--%s %s", key, value)
+	return fmt.Sprintf("--%s %v", key, value)
+}
+
+// parseReport parses output of "*:report" commands. Only lines with titles started with prefix are used,
+// keys are titles without prefix: "Git deploy branch: main" with prefix "Git " -> "deploy branch": "main"
+func parseReport(stdout string, prefix string) map[string]string {
+	res := make(map[string]string)
+	lines := strings.Split(stdout, "\n")
+	for _, line := range lines {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], prefix) {
+			continue
+		}
+		title := strings.TrimSpace(parts[0][len(prefix):])
+		res[title] = strings.TrimSpace(parts[1])
+	}
+	return res
 }
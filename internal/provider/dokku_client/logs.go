@@ -0,0 +1,45 @@
+package dokkuclient
+
+import (
+	"context"
+	"strings"
+)
+
+// LogsGlobalSet sets global property of logs plugin. Empty value unsets property
+func (c *Client) LogsGlobalSet(ctx context.Context, key string, value string) error {
+	return c.AppPropertySet(ctx, "logs", "--global", key, value)
+}
+
+// LogsGlobalReport returns global properties of logs plugin, e.g. "global vector sink" or "vector global image".
+// logs:report has no global mode, so report of first app is used. Returns nil if there are no apps
+func (c *Client) LogsGlobalReport(ctx context.Context) (map[string]string, error) {
+	apps, err := c.AppsList(ctx)
+	if err != nil || len(apps) == 0 {
+		return nil, err
+	}
+	return c.AppPropertiesReport(ctx, "logs", apps[0])
+}
+
+// LogsVectorStart starts vector container or recreates it to apply new image
+func (c *Client) LogsVectorStart(ctx context.Context) error {
+	_, _, err := c.RunQuiet(ctx, "logs:vector-start")
+	return err
+}
+
+func (c *Client) LogsVectorStop(ctx context.Context) error {
+	_, _, err := c.RunQuiet(ctx, "logs:vector-stop")
+	return err
+}
+
+// LogsVectorRunning returns true if vector container exists. Output of logs:vector-logs is used, because dokku
+// has no command to report container status
+func (c *Client) LogsVectorRunning(ctx context.Context) (bool, error) {
+	stdout, _, err := c.RunQuiet(ctx, "logs:vector-logs --num 1")
+	if err != nil {
+		if strings.Contains(stdout, "not running") || strings.Contains(stdout, "does not exist") || strings.Contains(stdout, "No such container") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -3,9 +3,65 @@ package dokkuclient
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 func (c *Client) ProcessRestart(ctx context.Context, appName string) error {
 	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("ps:restart %s", appName))
 	return err
 }
+
+func (c *Client) ProcessStart(ctx context.Context, appName string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("ps:start %s", appName))
+	return err
+}
+
+func (c *Client) ProcessStop(ctx context.Context, appName string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("ps:stop %s", appName))
+	return err
+}
+
+func (c *Client) ProcessReport(ctx context.Context, appName string) (map[string]string, error) {
+	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("ps:report %s", appName))
+	if err != nil {
+		return nil, err
+	}
+	return parseReport(stdout, ""), nil
+}
+
+// ProcessScaleReport returns count of containers for each process type
+func (c *Client) ProcessScaleReport(ctx context.Context, appName string) (map[string]int64, error) {
+	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("ps:scale %s", appName))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]int64)
+	for _, line := range strings.Split(stdout, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		// skips table header
+		count, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil {
+			continue
+		}
+		res[strings.TrimSpace(parts[0])] = count
+	}
+	return res, nil
+}
+
+func (c *Client) ProcessScale(ctx context.Context, appName string, scale map[string]int64, skipDeploy bool) error {
+	args := ""
+	if skipDeploy {
+		args = "--skip-deploy "
+	}
+	args += appName
+	for processType, count := range scale {
+		args = fmt.Sprintf("%s %s=%d", args, processType, count)
+	}
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("ps:scale %s", args))
+	return err
+}
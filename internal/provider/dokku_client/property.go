@@ -0,0 +1,30 @@
+package dokkuclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// AppPropertySet sets app property of plugin with "<plugin>:set" command. Empty value unsets property
+func (c *Client) AppPropertySet(ctx context.Context, plugin string, appName string, key string, value string) error {
+	cmd := fmt.Sprintf("%s:set %s %s", plugin, appName, key)
+	if value != "" {
+		cmd = fmt.Sprintf("%s %s", cmd, value)
+	}
+	_, _, err := c.RunQuiet(ctx, cmd)
+	return err
+}
+
+// AppPropertiesReport returns app properties of plugin from "<plugin>:report" command.
+// Keys are titles without plugin name: "Builder build dir" -> "build dir"
+func (c *Client) AppPropertiesReport(ctx context.Context, plugin string, appName string) (map[string]string, error) {
+	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("%s:report %s", plugin, appName))
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.ReplaceAll(plugin, "-", " ")
+	prefix = strings.ToUpper(prefix[:1]) + prefix[1:] + " "
+	return parseReport(stdout, prefix), nil
+}
@@ -3,6 +3,7 @@ package dokkuclient
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 func (c *Client) ProxyDisable(ctx context.Context, appName string) error {
@@ -14,3 +15,38 @@ func (c *Client) ProxyEnable(ctx context.Context, appName string) error {
 	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("proxy:enable %s", appName))
 	return err
 }
+
+func (c *Client) ProxyBuildConfig(ctx context.Context, appName string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("proxy:build-config %s", appName))
+	return err
+}
+
+// ProxySetType sets proxy implementation for app. Empty type unsets it to use global one
+func (c *Client) ProxySetType(ctx context.Context, appName string, proxyType string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("proxy:set %s %s", appName, proxyType))
+	return err
+}
+
+// ProxyLabelGet returns value of container label of app set for proxy (caddy, haproxy, traefik). Empty value means label is not set
+func (c *Client) ProxyLabelGet(ctx context.Context, proxyType string, appName string, label string) (string, error) {
+	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("%s:labels:show %s %s", proxyType, appName, label))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// ProxyLabelAdd adds container label of app. Arguments are split by whitespace by dokku over ssh and quotes are not
+// parsed, so value is passed as is and must not contain whitespace
+func (c *Client) ProxyLabelAdd(ctx context.Context, proxyType string, appName string, label string, value string) error {
+	if strings.ContainsAny(value, " \t\n") {
+		return fmt.Errorf("label value must not contain whitespace")
+	}
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("%s:labels:add %s %s %s", proxyType, appName, label, value))
+	return err
+}
+
+func (c *Client) ProxyLabelRemove(ctx context.Context, proxyType string, appName string, label string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("%s:labels:remove %s %s", proxyType, appName, label))
+	return err
+}
@@ -0,0 +1,74 @@
+package dokkuclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ResourceDefaultProcessType is used for limits and reservations applied to all process types
+const ResourceDefaultProcessType = "default"
+
+// ResourceReport returns resource values by process type, kind (limit or reserve) and resource name
+func (c *Client) ResourceReport(ctx context.Context, appName string) (map[string]map[string]map[string]string, error) {
+	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("resource:report %s", appName))
+	if err != nil {
+		return nil, err
+	}
+
+	re := regexp.MustCompile(`^(\S+) (limit|reserve) (.+)$`)
+	res := make(map[string]map[string]map[string]string)
+	for title, value := range parseReport(stdout, "") {
+		found := re.FindStringSubmatch(title)
+		if found == nil || value == "" {
+			continue
+		}
+
+		processType := found[1]
+		if processType == "_default_" || processType == "[default]" {
+			processType = ResourceDefaultProcessType
+		}
+		kind := found[2]
+		name := strings.ReplaceAll(found[3], " ", "-")
+
+		if res[processType] == nil {
+			res[processType] = make(map[string]map[string]string)
+		}
+		if res[processType][kind] == nil {
+			res[processType][kind] = make(map[string]string)
+		}
+		res[processType][kind][name] = value
+	}
+	return res, nil
+}
+
+// ResourceSet replaces all limits (kind "limit") or reservations (kind "reserve") of process type
+func (c *Client) ResourceSet(ctx context.Context, appName string, kind string, processType string, values map[string]string) error {
+	processTypeArg := ""
+	if processType != ResourceDefaultProcessType {
+		processTypeArg = fmt.Sprintf("--process-type %s ", processType)
+	}
+
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("resource:%s-clear %s%s", kind, processTypeArg, appName))
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := processTypeArg
+	for _, name := range names {
+		args += fmt.Sprintf("--%s %s ", name, values[name])
+	}
+	_, _, err = c.RunQuiet(ctx, fmt.Sprintf("resource:%s %s%s", kind, args, appName))
+	return err
+}
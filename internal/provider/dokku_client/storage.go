@@ -11,6 +11,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -20,24 +21,36 @@ import (
 
 const hostStoragePrefix = "/var/lib/dokku/data/storage/"
 
-func (c *Client) StorageExport(ctx context.Context, appName string) (res map[string]string, err error) {
+type Mount struct {
+	MountPath string
+	Options   []string
+}
+
+func (c *Client) StorageExport(ctx context.Context, appName string) (res map[string]Mount, err error) {
 	stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("storage:list %s", appName))
 	if err != nil {
 		return nil, err
 	}
 
-	res = make(map[string]string)
+	res = make(map[string]Mount)
 	lines := strings.Split(stdout, "\n")
 	for _, line := range lines {
 		if strings.TrimSpace(line) == "" {
 			continue
 		}
-		parts := strings.Split(line, ":")
+		// host-path:container-path[:options]
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 3)
 		hostpath := strings.TrimSpace(parts[0])
+		mount := Mount{
+			MountPath: strings.TrimSpace(parts[1]),
+		}
+		if len(parts) == 3 && parts[2] != "" {
+			mount.Options = strings.Split(strings.TrimSpace(parts[2]), ",")
+		}
 		if len(hostpath) > len(hostStoragePrefix) && hostpath[:len(hostStoragePrefix)] == hostStoragePrefix {
-			res[hostpath[len(hostStoragePrefix):]] = parts[1]
+			res[hostpath[len(hostStoragePrefix):]] = mount
 		} else {
-			res[hostpath] = parts[1]
+			res[hostpath] = mount
 		}
 	}
 	if len(res) == 0 {
@@ -56,19 +69,32 @@ func getPathToMount(name string) string {
 	return hostStoragePrefix + name
 }
 
-func (c *Client) StorageMount(ctx context.Context, appName string, name string, mountPath string) error {
-	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("storage:mount %s %s:%s", appName, getPathToMount(name), mountPath))
+func formatMount(name string, mountPath string, options []string) string {
+	if len(options) == 0 {
+		return fmt.Sprintf("%s:%s", getPathToMount(name), mountPath)
+	}
+	return fmt.Sprintf("%s:%s:%s", getPathToMount(name), mountPath, strings.Join(options, ","))
+}
+
+// StorageMount mounts storage to app. Options are docker volume options like "ro"
+func (c *Client) StorageMount(ctx context.Context, appName string, name string, mountPath string, options []string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("storage:mount %s %s", appName, formatMount(name, mountPath, options)))
 	return err
 }
 
-func (c *Client) StorageUnmount(ctx context.Context, appName string, name string, mountPath string) error {
-	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("storage:unmount %s %s:%s", appName, getPathToMount(name), mountPath))
+// StorageUnmount unmounts storage from app. Options must be the same as used for mount
+func (c *Client) StorageUnmount(ctx context.Context, appName string, name string, mountPath string, options []string) error {
+	_, _, err := c.RunQuiet(ctx, fmt.Sprintf("storage:unmount %s %s", appName, formatMount(name, mountPath, options)))
 	return err
 }
 
-func (c *Client) storageEnsureDirectory(ctx context.Context, name string) error {
+func (c *Client) storageEnsureDirectory(ctx context.Context, name string, chown string) error {
 	if name != "" && name[0] != '/' {
-		_, _, err := c.RunQuiet(ctx, fmt.Sprintf("storage:ensure-directory %s", name))
+		args := ""
+		if chown != "" {
+			args = DoubleDashArg("chown", chown)
+		}
+		_, _, err := c.RunQuiet(ctx, fmt.Sprintf("storage:ensure-directory %s %s", args, name))
 		if err != nil {
 			return err
 		}
@@ -76,8 +102,10 @@ func (c *Client) storageEnsureDirectory(ctx context.Context, name string) error
 	return nil
 }
 
-func (c *Client) StorageEnsure(ctx context.Context, name string, localDirectory *string) error {
-	err := c.storageEnsureDirectory(ctx, name)
+// StorageEnsure creates storage directory and uploads local directory to it if provided.
+// Empty chown means default chown option (herokuish)
+func (c *Client) StorageEnsure(ctx context.Context, name string, chown string, localDirectory *string) error {
+	err := c.storageEnsureDirectory(ctx, name, chown)
 	if err != nil {
 		return fmt.Errorf("unable to ensure storage: %w", err)
 	}
@@ -89,7 +117,7 @@ func (c *Client) StorageEnsure(ctx context.Context, name string, localDirectory
 		}
 
 		// Run ensure again to restore permissions
-		err = c.storageEnsureDirectory(ctx, name)
+		err = c.storageEnsureDirectory(ctx, name, chown)
 		if err != nil {
 			return fmt.Errorf("unable to ensure storage: %w", err)
 		}
@@ -98,6 +126,36 @@ func (c *Client) StorageEnsure(ctx context.Context, name string, localDirectory
 	return nil
 }
 
+// StorageDestroy removes all content of storage directory. Only storage directory is mounted to helper app,
+// so empty directory itself is left on host
+func (c *Client) StorageDestroy(ctx context.Context, name string) error {
+	if name == "" || name[0] == '.' || strings.ContainsAny(name, "/ ") {
+		return fmt.Errorf("only named storages can be destroyed")
+	}
+
+	err := c.withUploadApp(ctx, name, func(appName string) error {
+		_, _, err := c.RunQuiet(ctx, fmt.Sprintf("enter %s web find /mnt -mindepth 1 -delete", appName))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("unable to destroy storage: %w", err)
+	}
+	return nil
+}
+
+// StorageDownload writes tar archive with storage content to writer
+func (c *Client) StorageDownload(ctx context.Context, name string, writer io.Writer) error {
+	tflog.Debug(ctx, "Downloading storage", map[string]any{"storage": name})
+
+	err := c.withUploadApp(ctx, name, func(appName string) error {
+		return c.copyFromRemoteHost(ctx, appName, writer)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to download storage: %w", err)
+	}
+	return nil
+}
+
 func (c *Client) makeTarArchiveForDirectory(ctx context.Context, localDirectory string, writer io.Writer) error {
 	if _, err := os.Stat(localDirectory); os.IsNotExist(err) {
 		return fmt.Errorf("Directory %s does not exist", localDirectory)
@@ -154,11 +212,136 @@ func (c *Client) makeTarArchiveForDirectory(ctx context.Context, localDirectory
 	return err
 }
 
-// / dokku apps:create <APP_NAME>
+type StorageFile struct {
+	Path    string
+	Content []byte
+	Mode    os.FileMode
+	Owner   string
+}
+
+// StorageFilesUpload uploads files to storage and removes files by provided paths. Paths are relative to storage root
+func (c *Client) StorageFilesUpload(ctx context.Context, name string, files []StorageFile, pathsToRemove []string) error {
+	tflog.Debug(ctx, "Uploading files to storage", map[string]any{"storage": name, "files": len(files), "remove": pathsToRemove})
+
+	var commands []string
+	for _, p := range pathsToRemove {
+		commands = append(commands, fmt.Sprintf("rm -f '/mnt/%s'", p))
+	}
+	// by default files and created directories are owned by the owner of storage directory
+	defaultOwner := "$(stat -c %u:%g /mnt)"
+	for _, dir := range storageFilesParentDirectories(files) {
+		commands = append(commands, fmt.Sprintf("chown %s '/mnt/%s'", defaultOwner, dir))
+	}
+	for _, f := range files {
+		owner := f.Owner
+		if owner == "" {
+			owner = defaultOwner
+		}
+		commands = append(commands, fmt.Sprintf("chown %s '/mnt/%s'", owner, f.Path))
+		commands = append(commands, fmt.Sprintf("chmod %o '/mnt/%s'", f.Mode, f.Path))
+	}
+
+	var makeArchive func(writer io.Writer) error
+	if len(files) != 0 {
+		makeArchive = func(writer io.Writer) error {
+			return makeTarArchiveForFiles(files, writer)
+		}
+	}
+
+	err := c.withUploadApp(ctx, name, func(appName string) error {
+		return c.copyToRemoteHost(ctx, appName, makeArchive, commands)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to upload files: %w", err)
+	}
+	return nil
+}
+
+// StorageFilesChecksums returns sha256 checksums of files in storage. Missing files are not present in result
+func (c *Client) StorageFilesChecksums(ctx context.Context, name string, paths []string) (res map[string]string, err error) {
+	remotePaths := make([]string, len(paths))
+	for i, p := range paths {
+		remotePaths[i] = "/mnt/" + p
+	}
+
+	res = make(map[string]string)
+	err = c.withUploadApp(ctx, name, func(appName string) error {
+		stdout, _, err := c.RunQuiet(ctx, fmt.Sprintf("enter %s web sha256sum %s", appName, strings.Join(remotePaths, " ")))
+		// sha256sum exits with non-zero status if some of files are missing
+		if err != nil && !strings.Contains(stdout, "No such file or directory") {
+			return err
+		}
+
+		lines := strings.Split(stdout, "\n")
+		for _, line := range lines {
+			parts := strings.Fields(line)
+			if len(parts) != 2 || len(parts[0]) != 64 || !strings.HasPrefix(parts[1], "/mnt/") {
+				continue
+			}
+			res[parts[1][len("/mnt/"):]] = parts[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get checksums: %w", err)
+	}
+	return
+}
+
+func storageFilesParentDirectories(files []StorageFile) (dirs []string) {
+	known := make(map[string]bool)
+	for _, f := range files {
+		dir := filepath.Dir(f.Path)
+		for dir != "." && dir != "/" && !known[dir] {
+			known[dir] = true
+			dirs = append(dirs, dir)
+			dir = filepath.Dir(dir)
+		}
+	}
+	sort.Strings(dirs)
+	return
+}
+
+func makeTarArchiveForFiles(files []StorageFile, writer io.Writer) error {
+	tarWriter := tar.NewWriter(writer)
+	defer tarWriter.Close()
+
+	for _, dir := range storageFilesParentDirectories(files) {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     dir + "/",
+			Mode:     0755,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to write tar header: %w", err)
+		}
+	}
+
+	for _, f := range files {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.Path,
+			Mode:     int64(f.Mode),
+			Size:     int64(len(f.Content)),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to write tar header: %w", err)
+		}
+
+		_, err = tarWriter.Write(f.Content)
+		if err != nil {
+			return fmt.Errorf("unable to write file to tar archive: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// / dokku apps:create <APP_NAME> (or reuse persistent helper app, see withUploadApp)
 // / dokku checks:disable <APP_NAME>
 // / dokku config:set <APP_NAME> DOKKU_DOCKERFILE_START_CMD='sleep infinity'
 // / dokku storage:mount <APP_NAME> <REMOTE_DIRECTORY>:/mnt
-// / dokku git:from-image <APP_NAME> busybox
+// / dokku git:from-image <APP_NAME> <UPLOAD_IMAGE>
 // / dokku enter <APP_NAME> web sh
 // /     ## in pseudo-tty
 // /     # remove old tmp archive (if present)
@@ -170,56 +353,27 @@ func (c *Client) makeTarArchiveForDirectory(ctx context.Context, localDirectory
 // /     # remove tmp archive
 // /     rm -f /mnt/tmp.tar.base64
 // /     exit
-// / dokku apps:destroy --force <APP_NAME>
+// / dokku apps:destroy --force <APP_NAME> (if helper app is not persistent)
 func (c *Client) storageSyncDirectories(ctx context.Context, storageName string, localDirectory string, remoteDirectory string) error {
 	tflog.Debug(ctx, "Uploading local directory to remote", map[string]any{"local_directory": localDirectory, "remote_directory": remoteDirectory})
 
-	appName := c.uploadAppName
-	err := c.AppCreate(ctx, appName)
-	if err != nil {
-		return fmt.Errorf("unable to create app: %w", err)
-	}
-
-	defer func() {
-		_ = c.AppDestroy(ctx, appName)
-	}()
-
-	err = c.ChecksSet(ctx, appName, "disabled")
-	if err != nil {
-		return fmt.Errorf("unable to disable checks: %w", err)
-	}
-
-	err = c.ConfigSet(ctx, appName, map[string]string{
-		"DOKKU_DOCKERFILE_START_CMD": "sleep infinity",
+	return c.withUploadApp(ctx, storageName, func(appName string) error {
+		// _, _, err = c.Run(ctx, fmt.Sprintf("run %s find /mnt -mindepth 1 -delete", appName))
+		// if err != nil {
+		// 	return fmt.Errorf("unable to clear mounted directory: %w", err)
+		// }
+
+		// -- copy tar archive to remote host
+		return c.copyToRemoteHost(ctx, appName, func(writer io.Writer) error {
+			return c.makeTarArchiveForDirectory(ctx, localDirectory, writer)
+		}, nil)
+		// --
 	})
-	if err != nil {
-		return fmt.Errorf("unable to set config: %w", err)
-	}
-
-	err = c.StorageMount(ctx, appName, storageName, "/mnt")
-	if err != nil {
-		return fmt.Errorf("unable to mount storage: %w", err)
-	}
-
-	deployed, err := c.DeployFromImage(ctx, appName, "busybox", false)
-	if err != nil {
-		return fmt.Errorf("unable to deploy sync app: %w", err)
-	}
-	if !deployed {
-		return fmt.Errorf("sync app wasn't deployed")
-	}
-
-	// _, _, err = c.Run(ctx, fmt.Sprintf("run %s find /mnt -mindepth 1 -delete", appName))
-	// if err != nil {
-	// 	return fmt.Errorf("unable to clear mounted directory: %w", err)
-	// }
-
-	// -- copy tar archive to remote host
-	return c.copyToRemoteHost(ctx, appName, localDirectory)
-	// --
 }
 
-func (c *Client) copyToRemoteHost(ctx context.Context, appName string, localDirectory string) error {
+// copyToRemoteHost uploads tar archive made by makeArchive to /mnt of helper app and then runs provided shell commands.
+// If makeArchive is nil then only commands are run
+func (c *Client) copyToRemoteHost(ctx context.Context, appName string, makeArchive func(writer io.Writer) error, commands []string) error {
 	session, err := c.client.NewSession()
 	if err != nil {
 		return fmt.Errorf("unable to open ssh session: %w", err)
@@ -249,7 +403,67 @@ func (c *Client) copyToRemoteHost(ctx context.Context, appName string, localDire
 		return fmt.Errorf("unable to start copying files to remote directory: %w", err)
 	}
 
-	_, err = io.WriteString(stdin, "rm -f /mnt/tmp.tar.base64\n")
+	if makeArchive != nil {
+		err = c.writeArchiveToRemoteHost(stdin, makeArchive)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, command := range commands {
+		_, err = io.WriteString(stdin, command+"\n")
+		if err != nil {
+			return fmt.Errorf("unable to run command: %w", err)
+		}
+	}
+
+	_, err = io.WriteString(stdin, "exit\n")
+	if err != nil {
+		return fmt.Errorf("unable to write string to file: %w", err)
+	}
+
+	err = stdin.Close()
+	if err != nil {
+		return fmt.Errorf("unable to close stdin: %w", err)
+	}
+
+	// stdout := sessionStdoutCollector.b.Bytes()
+	// fmt.Println("--------------\n" + string(stdout) + "\n--------------")
+
+	err = session.Wait()
+	if err != nil {
+		return fmt.Errorf("unable to copy: %w", err)
+	}
+
+	return nil
+}
+
+// copyFromRemoteHost streams tar archive of /mnt of helper app to writer.
+// Session is started without pseudo terminal so binary output is not corrupted
+func (c *Client) copyFromRemoteHost(ctx context.Context, appName string, writer io.Writer) error {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("unable to open ssh session: %w", err)
+	}
+	defer session.Close()
+
+	var sessionStderrCollector singleWriter
+	session.Stdout = writer
+	session.Stderr = &sessionStderrCollector
+
+	cmd := fmt.Sprintf("--quiet enter %s web tar c -C /mnt .", appName)
+	tflog.Debug(ctx, "SSH cmd", map[string]any{"cmd": cmd})
+
+	err = session.Run(cmd)
+	if err != nil {
+		return fmt.Errorf("unable to copy: %w: %s", err, sessionStderrCollector.b.String())
+	}
+
+	return nil
+}
+
+func (c *Client) writeArchiveToRemoteHost(stdin io.Writer, makeArchive func(writer io.Writer) error) error {
+	_, err := io.WriteString(stdin, "rm -f /mnt/tmp.tar.base64\n")
 	if err != nil {
 		return fmt.Errorf("unable to write string to file: %w", err)
 	}
@@ -265,7 +479,7 @@ func (c *Client) copyToRemoteHost(ctx context.Context, appName string, localDire
 			}
 		}()
 
-		err := c.makeTarArchiveForDirectory(ctx, localDirectory, base64encoder)
+		err := makeArchive(base64encoder)
 		if err != nil {
 			// return fmt.Errorf("unable to make tar archive: %w", err)
 			log.Printf("[error] unable to make tar archive: %v\n", err)
@@ -312,24 +526,6 @@ func (c *Client) copyToRemoteHost(ctx context.Context, appName string, localDire
 		return fmt.Errorf("unable to remove tmp archive: %w", err)
 	}
 
-	_, err = io.WriteString(stdin, "exit\n")
-	if err != nil {
-		return fmt.Errorf("unable to write string to file: %w", err)
-	}
-
-	err = stdin.Close()
-	if err != nil {
-		return fmt.Errorf("unable to close stdin: %w", err)
-	}
-
-	// stdout := sessionStdoutCollector.b.Bytes()
-	// fmt.Println("--------------\n" + string(stdout) + "\n--------------")
-
-	err = session.Wait()
-	if err != nil {
-		return fmt.Errorf("unable to copy: %w", err)
-	}
-
 	return nil
 }
 
@@ -0,0 +1,190 @@
+package dokkuclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// withUploadApp prepares helper app with storage mounted as /mnt and runs fn.
+//
+// Temporary helper app is named "<upload_app_name>-<random suffix>" and destroyed after fn is finished.
+// Persistent helper app is named "<upload_app_name>", it is deployed once per run and kept between runs,
+// only mounted storage is changed (with restart).
+func (c *Client) withUploadApp(ctx context.Context, storageName string, fn func(appName string) error) error {
+	c.cleanupUploadApps(ctx)
+
+	if c.uploadAppPersistent {
+		// persistent app can't be used for several storages at once
+		c.uploadMutex.Lock()
+		defer c.uploadMutex.Unlock()
+
+		appName := c.uploadAppName
+		err := c.preparePersistentUploadApp(ctx, appName, storageName)
+		if err != nil {
+			return err
+		}
+		return fn(appName)
+	}
+
+	suffix := make([]byte, 4)
+	_, err := rand.Read(suffix)
+	if err != nil {
+		return fmt.Errorf("unable to generate app name: %w", err)
+	}
+	appName := fmt.Sprintf("%s-%s", c.uploadAppName, hex.EncodeToString(suffix))
+
+	err = c.AppCreate(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("unable to create app: %w", err)
+	}
+
+	defer func() {
+		_ = c.AppDestroy(ctx, appName)
+	}()
+
+	err = c.setupUploadApp(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	err = c.StorageMount(ctx, appName, storageName, "/mnt", nil)
+	if err != nil {
+		return fmt.Errorf("unable to mount storage: %w", err)
+	}
+
+	deployed, err := c.DeployFromImage(ctx, appName, c.uploadImage, false)
+	if err != nil {
+		return fmt.Errorf("unable to deploy sync app: %w", err)
+	}
+	if !deployed {
+		return fmt.Errorf("sync app wasn't deployed")
+	}
+
+	return fn(appName)
+}
+
+func (c *Client) setupUploadApp(ctx context.Context, appName string) error {
+	err := c.ChecksSet(ctx, appName, "disabled")
+	if err != nil {
+		return fmt.Errorf("unable to disable checks: %w", err)
+	}
+
+	err = c.ConfigSet(ctx, appName, map[string]string{
+		"DOKKU_DOCKERFILE_START_CMD": "sleep infinity",
+		uploadAppCreatedAtKey:        strconv.FormatInt(time.Now().Unix(), 10),
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("unable to set config: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) preparePersistentUploadApp(ctx context.Context, appName string, storageName string) error {
+	exists, err := c.AppExists(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("unable to check app existence: %w", err)
+	}
+	if !exists {
+		err = c.AppCreate(ctx, appName)
+		if err != nil {
+			return fmt.Errorf("unable to create app: %w", err)
+		}
+
+		err = c.setupUploadApp(ctx, appName)
+		if err != nil {
+			return err
+		}
+	}
+
+	mounts, err := c.StorageExport(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("unable to get mounted storage: %w", err)
+	}
+	mounted := false
+	for name, mount := range mounts {
+		if mount.MountPath != "/mnt" {
+			continue
+		}
+		if name == storageName {
+			mounted = true
+			continue
+		}
+		err = c.StorageUnmount(ctx, appName, name, mount.MountPath, mount.Options)
+		if err != nil {
+			return fmt.Errorf("unable to unmount storage: %w", err)
+		}
+	}
+	if !mounted {
+		err = c.StorageMount(ctx, appName, storageName, "/mnt", nil)
+		if err != nil {
+			return fmt.Errorf("unable to mount storage: %w", err)
+		}
+	}
+
+	restartRequired := !mounted
+	if !c.uploadAppDeployed {
+		deployed, err := c.DeployFromImage(ctx, appName, c.uploadImage, false)
+		if err != nil {
+			return fmt.Errorf("unable to deploy sync app: %w", err)
+		}
+		c.uploadAppDeployed = true
+		// same image is already deployed - make sure that app is running
+		restartRequired = !deployed
+	}
+
+	if restartRequired {
+		err = c.ProcessRestart(ctx, appName)
+		if err != nil {
+			return fmt.Errorf("unable to restart sync app: %w", err)
+		}
+	}
+	return nil
+}
+
+// uploadAppCreatedAtKey is config key with unix time of helper app creation, used to find stale helper apps
+const uploadAppCreatedAtKey = "TERRAFORM_UPLOAD_APP_CREATED_AT"
+
+// uploadAppStaleAfter is age after which temporary helper app is considered left from crashed run
+const uploadAppStaleAfter = 24 * time.Hour
+
+// cleanupUploadApps destroys temporary helper apps left from crashed runs. It is done once per run.
+// Apps of other runs may be still in use, so only apps created long ago are destroyed
+func (c *Client) cleanupUploadApps(ctx context.Context) {
+	c.uploadCleanupOnce.Do(func() {
+		apps, err := c.AppsList(ctx)
+		if err != nil {
+			tflog.Warn(ctx, "Unable to list apps to cleanup sync apps", map[string]any{"error": err.Error()})
+			return
+		}
+
+		re := regexp.MustCompile("^" + regexp.QuoteMeta(c.uploadAppName) + "-[0-9a-f]{8}$")
+		for _, appName := range apps {
+			if !re.MatchString(appName) {
+				continue
+			}
+
+			config, err := c.ConfigExport(ctx, appName)
+			if err != nil {
+				tflog.Warn(ctx, "Unable to get config of sync app", map[string]any{"app": appName, "error": err.Error()})
+				continue
+			}
+			createdAt, err := strconv.ParseInt(config[uploadAppCreatedAtKey], 10, 64)
+			if err != nil || time.Since(time.Unix(createdAt, 0)) < uploadAppStaleAfter {
+				continue
+			}
+
+			tflog.Info(ctx, "Destroying sync app left from previous run", map[string]any{"app": appName})
+			err = c.AppDestroy(ctx, appName)
+			if err != nil {
+				tflog.Warn(ctx, "Unable to destroy sync app", map[string]any{"app": appName, "error": err.Error()})
+			}
+		}
+	})
+}
@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+
+	dokkuclient "terraform-provider-dokku/internal/provider/dokku_client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+var (
+	_ resource.Resource              = &logsResource{}
+	_ resource.ResourceWithConfigure = &logsResource{}
+)
+
+func NewLogsResource() resource.Resource {
+	return &logsResource{}
+}
+
+type logsResource struct {
+	client *dokkuclient.Client
+}
+
+type logsResourceModel struct {
+	VectorImage   types.String `tfsdk:"vector_image"`
+	VectorSink    types.String `tfsdk:"vector_sink"`
+	MaxSize       types.String `tfsdk:"max_size"`
+	VectorRunning types.Bool   `tfsdk:"vector_running"`
+}
+
+// Metadata returns the resource type name.
+func (r *logsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_logs"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *logsResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	//nolint:forcetypeassert
+	r.client = req.ProviderData.(*dokkuclient.Client)
+}
+
+// Schema defines the schema for the resource.
+func (r *logsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Global logs setup (logs:set --global). Only one instance should be defined. Omitted properties are not managed. Properties are checked on refresh only if there is at least one app",
+		Attributes: map[string]schema.Attribute{
+			"vector_image": schema.StringAttribute{
+				Optional:    true,
+				Description: "Docker image of vector container, e.g. timberio/vector:0.38.0-debian",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"vector_sink": schema.StringAttribute{
+				Optional:    true,
+				Description: "Vector sink for logs of all apps in DSN format, e.g. loki://?endpoint=http://loki:3100&encoding[codec]=json",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9_]+://\S*$`), "must be in DSN format without spaces"),
+				},
+			},
+			"max_size": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default max size of container log file, e.g. 10m, or unlimited. Applied to new containers",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^([0-9]+[kmg]?|unlimited)$`), "must be size with optional k, m or g unit, or unlimited"),
+				},
+			},
+			"vector_running": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Desired state of vector container: true to start it (logs:vector-start), false to stop it (logs:vector-stop). Existence of container is checked on refresh. Default: not managed",
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *logsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state logsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	report, err := r.client.LogsGlobalReport(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read logs setup", "Unable to read logs setup. "+err.Error())
+		return
+	}
+	// global properties can't be read without apps
+	if report == nil {
+		resp.Diagnostics.AddWarning("Unable to check global logs setup", "Unable to check global logs setup. Global properties are reported by logs:report of app, but there are no apps")
+	} else {
+		for _, property := range logsGlobalProperties(&state) {
+			if property.value.IsNull() {
+				continue
+			}
+			value := basetypes.NewStringNull()
+			if title := logsGlobalReportTitles[property.key]; report[title] != "" {
+				value = basetypes.NewStringValue(report[title])
+			}
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(property.attr), value)...)
+		}
+	}
+
+	if !state.VectorRunning.IsNull() {
+		running, err := r.client.LogsVectorRunning(ctx)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("vector_running"), "Unable to get vector state", "Unable to get vector state. "+err.Error())
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vector_running"), running)...)
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *logsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan logsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.setProperties(ctx, logsGlobalProperties(&plan), nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.VectorRunning.IsNull() {
+		r.setVectorRunning(ctx, plan.VectorRunning.ValueBool(), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *logsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan logsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var state logsResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.setProperties(ctx, logsGlobalProperties(&plan), logsGlobalProperties(&state), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.VectorRunning.IsNull() {
+		// running vector container is recreated to use new image
+		imageChanged := plan.VectorRunning.ValueBool() && !plan.VectorImage.Equal(state.VectorImage)
+		if !plan.VectorRunning.Equal(state.VectorRunning) || imageChanged {
+			r.setVectorRunning(ctx, plan.VectorRunning.ValueBool(), &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *logsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state logsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.setProperties(ctx, logsGlobalProperties(&logsResourceModel{}), logsGlobalProperties(&state), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.VectorRunning.ValueBool() {
+		r.setVectorRunning(ctx, false, &resp.Diagnostics)
+	}
+}
+
+// logsGlobalProperties returns global properties of logs plugin with values from model
+func logsGlobalProperties(model *logsResourceModel) []appProperty {
+	return []appProperty{
+		{"logs", "vector-image", "vector_image", model.VectorImage},
+		{"logs", "vector-sink", "vector_sink", model.VectorSink},
+		{"logs", "max-size", "max_size", model.MaxSize},
+	}
+}
+
+// logsGlobalReportTitles maps keys of global properties to titles of logs:report
+var logsGlobalReportTitles = map[string]string{
+	"vector-image": "vector global image",
+	"vector-sink":  "global vector sink",
+	"max-size":     "global max size",
+}
+
+// setProperties sets properties changed in plan. state is nil on create
+func (r *logsResource) setProperties(ctx context.Context, plan []appProperty, state []appProperty, diags *diag.Diagnostics) {
+	for i, property := range plan {
+		if state != nil && property.value.Equal(state[i].value) {
+			continue
+		}
+		if state == nil && property.value.IsNull() {
+			continue
+		}
+		err := r.client.LogsGlobalSet(ctx, property.key, property.value.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root(property.attr), "Unable to set property", "Unable to set property. "+err.Error())
+		}
+	}
+}
+
+func (r *logsResource) setVectorRunning(ctx context.Context, running bool, diags *diag.Diagnostics) {
+	if running {
+		err := r.client.LogsVectorStart(ctx)
+		if err != nil {
+			diags.AddAttributeError(path.Root("vector_running"), "Unable to start vector", "Unable to start vector. "+err.Error())
+		}
+	} else {
+		err := r.client.LogsVectorStop(ctx)
+		if err != nil {
+			diags.AddAttributeError(path.Root("vector_running"), "Unable to stop vector", "Unable to stop vector. "+err.Error())
+		}
+	}
+}
@@ -42,13 +42,15 @@ type dokkuProvider struct{}
 
 // dokkuProviderModel describes the provider data model.
 type dokkuProviderModel struct {
-	SshHost          types.String `tfsdk:"ssh_host"`
-	SshPort          types.Int64  `tfsdk:"ssh_port"`
-	SshUser          types.String `tfsdk:"ssh_user"`
-	SshCert          types.String `tfsdk:"ssh_cert"`
-	LogSshCommands   types.Bool   `tfsdk:"log_ssh_commands"`
-	UploadAppName    types.String `tfsdk:"upload_app_name"`
-	UploadSplitBytes types.Int64  `tfsdk:"upload_split_bytes"`
+	SshHost             types.String `tfsdk:"ssh_host"`
+	SshPort             types.Int64  `tfsdk:"ssh_port"`
+	SshUser             types.String `tfsdk:"ssh_user"`
+	SshCert             types.String `tfsdk:"ssh_cert"`
+	LogSshCommands      types.Bool   `tfsdk:"log_ssh_commands"`
+	UploadAppName       types.String `tfsdk:"upload_app_name"`
+	UploadSplitBytes    types.Int64  `tfsdk:"upload_split_bytes"`
+	UploadImage         types.String `tfsdk:"upload_image"`
+	UploadAppPersistent types.Bool   `tfsdk:"upload_app_persistent"`
 }
 
 func (p *dokkuProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -94,11 +96,14 @@ func (p *dokkuProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 					"",
 					"Since dokku don't allow to upload files directly, workaround is used.",
 					"Algorithm is:",
-					"1. Create helper application, using name, provided in this attribute",
+					"1. Create helper application, using name, provided in this attribute, with random suffix",
 					"2. Mount desired remote directory as /mnt",
-					"3. Deploy \"busybox\" docker image deployed to app",
+					"3. Deploy docker image from \"upload_image\" attribute to app",
 					"4. [on client side] Create tar archive for local_directory and encode it using base64",
 					"5. Connect to app using \"dokku enter\" and use a bunch of echo-s to make file \"tmp.tar.base64\", and then decode and un-tar it to /mnt",
+					"6. Destroy helper application",
+					"",
+					"Helper applications with this name and random suffix, left from crashed runs more than 24 hours ago, are destroyed before first upload.",
 				}, "\n"),
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
@@ -118,6 +123,29 @@ func (p *dokkuProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 					int64validator.AtLeast(1),
 				},
 			},
+			"upload_image": schema.StringAttribute{
+				Optional: true,
+				Description: strings.Join([]string{
+					"This attribute is used to upload local files to remote server using storage.local_directory attribute.",
+					"Docker image to deploy to helper application. See details in description to \"upload_app_name\" attribute. Default: busybox",
+					"",
+					"Image must provide sh, tar, base64, sha256sum, stat, chown and chmod (busybox-compatible).",
+					"Use it to deploy image from local registry mirror if docker hub is not accessible.",
+				}, "\n"),
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"upload_app_persistent": schema.BoolAttribute{
+				Optional: true,
+				Description: strings.Join([]string{
+					"This attribute is used to upload local files to remote server using storage.local_directory attribute.",
+					"Use one persistent helper application, named exactly as \"upload_app_name\", instead of creating and destroying application for every upload. Default: false",
+					"",
+					"Helper application is deployed once per run and is kept after run, only mounted directory is changed between uploads.",
+					"Uploads are not run in parallel in this mode.",
+				}, "\n"),
+			},
 		},
 	}
 }
@@ -177,6 +205,8 @@ func (p *dokkuProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	logSshCommands := false
 	uploadAppName := "storage-sync"
 	uploadSplitBytes := 256
+	uploadImage := "busybox"
+	uploadAppPersistent := false
 
 	if !config.SshHost.IsNull() {
 		host = config.SshHost.ValueString()
@@ -204,6 +234,12 @@ func (p *dokkuProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	if !config.UploadSplitBytes.IsNull() {
 		uploadSplitBytes = int(config.UploadSplitBytes.ValueInt64())
 	}
+	if !config.UploadImage.IsNull() {
+		uploadImage = config.UploadImage.ValueString()
+	}
+	if !config.UploadAppPersistent.IsNull() {
+		uploadAppPersistent = config.UploadAppPersistent.ValueBool()
+	}
 
 	usr, err := user.Current()
 	if err == nil {
@@ -259,7 +295,7 @@ func (p *dokkuProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
-	dokkuClient := dokkuclient.New(client, logSshCommands, uploadAppName, uploadSplitBytes)
+	dokkuClient := dokkuclient.New(client, logSshCommands, uploadAppName, uploadSplitBytes, uploadImage, uploadAppPersistent)
 	rawVersion, version, err := dokkuClient.GetVersion(ctx)
 	if err != nil {
 		if err == dokkuclient.ErrInvalidUser {
@@ -297,10 +333,13 @@ func (p *dokkuProvider) Configure(ctx context.Context, req provider.ConfigureReq
 func (p *dokkuProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewAppResource,
+		NewCertificateResource,
 		NewDomainResource,
 		NewHttpAuthResource,
 		NewLetsencryptResource,
+		NewLogsResource,
 		NewPluginResource,
+		NewStorageResource,
 
 		services.NewClickhouseLinkResource,
 		services.NewClickhouseResource,
@@ -328,7 +367,9 @@ func (p *dokkuProvider) Resources(ctx context.Context) []func() resource.Resourc
 }
 
 func (p *dokkuProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewStorageArchiveDataSource,
+	}
 }
 
 func verifyHost(host string, remote net.Addr, key ssh.PublicKey) error {
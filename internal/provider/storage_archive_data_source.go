@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dokkuclient "terraform-provider-dokku/internal/provider/dokku_client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+var (
+	_ datasource.DataSource              = &storageArchiveDataSource{}
+	_ datasource.DataSourceWithConfigure = &storageArchiveDataSource{}
+)
+
+func NewStorageArchiveDataSource() datasource.DataSource {
+	return &storageArchiveDataSource{}
+}
+
+type storageArchiveDataSource struct {
+	client *dokkuclient.Client
+}
+
+type storageArchiveDataSourceModel struct {
+	Name         types.String `tfsdk:"name"`
+	OutputPath   types.String `tfsdk:"output_path"`
+	OutputSha256 types.String `tfsdk:"output_sha256"`
+	OutputSize   types.Int64  `tfsdk:"output_size"`
+}
+
+// Metadata returns the data source type name.
+func (d *storageArchiveDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_storage_archive"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *storageArchiveDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	//nolint:forcetypeassert
+	d.client = req.ProviderData.(*dokkuclient.Client)
+}
+
+// Schema defines the schema for the data source.
+func (d *storageArchiveDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: strings.Join([]string{
+			"Downloads content of storage to local tar archive.",
+			"",
+			"Helper app is used the same way as for uploading (see upload_* attributes in provider configuration).",
+			"Should not be used for downloading large storages, because all content is streamed over SSH on each read.",
+		}, "\n"),
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Storage name or absolute path to host directory",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"output_path": schema.StringAttribute{
+				Required:    true,
+				Description: "Local path to write tar archive to",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"output_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA256 checksum of written archive",
+			},
+			"output_size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Size of written archive in bytes",
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *storageArchiveDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data storageArchiveDataSourceModel
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	outputPath := data.OutputPath.ValueString()
+	err := os.MkdirAll(filepath.Dir(outputPath), os.ModePerm)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("output_path"), "Unable to create directory", "Unable to create directory. "+err.Error())
+		return
+	}
+
+	// archive is written to temporary file and moved to output path, so partial archive is never left there
+	file, err := os.CreateTemp(filepath.Dir(outputPath), "."+filepath.Base(outputPath)+".*")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("output_path"), "Unable to create file", "Unable to create file. "+err.Error())
+		return
+	}
+	defer file.Close()
+	defer os.Remove(file.Name())
+
+	// temporary file is created with 0600 mode
+	err = file.Chmod(0644)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("output_path"), "Unable to create file", "Unable to create file. "+err.Error())
+		return
+	}
+
+	hash := sha256.New()
+	counter := &countingWriter{}
+	err = d.client.StorageDownload(ctx, data.Name.ValueString(), io.MultiWriter(file, hash, counter))
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to download storage", "Unable to download storage. "+err.Error())
+		return
+	}
+
+	err = file.Close()
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("output_path"), "Unable to write file", "Unable to write file. "+err.Error())
+		return
+	}
+
+	err = os.Rename(file.Name(), outputPath)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("output_path"), "Unable to write file", "Unable to write file. "+err.Error())
+		return
+	}
+
+	data.OutputSha256 = basetypes.NewStringValue(fmt.Sprintf("%x", hash.Sum(nil)))
+	data.OutputSize = basetypes.NewInt64Value(counter.n)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	dokkuclient "terraform-provider-dokku/internal/provider/dokku_client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type storageFileModel struct {
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	Mode          types.String `tfsdk:"mode"`
+	Owner         types.String `tfsdk:"owner"`
+}
+
+func storageFilesSchemaAttribute() schema.MapNestedAttribute {
+	return schema.MapNestedAttribute{
+		Optional: true,
+		Description: strings.Join([]string{
+			"Files to place into storage. Keys are paths relative to storage root",
+			"",
+			"Files are uploaded the same way as local_directory (see upload_* attributes in provider configuration).",
+			"Checksums of uploaded files are checked on refresh, so changed or removed files will be uploaded again.",
+		}, "\n"),
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"content": schema.StringAttribute{
+					Optional:    true,
+					Description: "Content of file",
+					Validators: []validator.String{
+						stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("content_base64")),
+					},
+				},
+				"content_base64": schema.StringAttribute{
+					Optional:    true,
+					Description: "Base64-encoded content of file. Use it for binary files",
+					Validators: []validator.String{
+						stringvalidator.LengthAtLeast(1),
+					},
+				},
+				"mode": schema.StringAttribute{
+					Optional:    true,
+					Description: "File mode in octal notation. Default: 0644",
+					Validators: []validator.String{
+						stringvalidator.RegexMatches(regexp.MustCompile(`^0?[0-7]{3}$`), "Must be octal file mode"),
+					},
+				},
+				"owner": schema.StringAttribute{
+					Optional:    true,
+					Description: "Numeric owner of file in format uid[:gid], e.g. 32767:32767. Names are not used because they are resolved inside helper app, not on host. Default: owner of storage directory",
+					Validators: []validator.String{
+						stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9]+(:[0-9]+)?$`), "Must be in format uid[:gid]"),
+					},
+				},
+			},
+		},
+		Validators: []validator.Map{
+			mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9._-]+(/[a-zA-Z0-9._-]+)*$`), "Must be relative path")),
+		},
+	}
+}
+
+func validateStorageFiles(files map[string]storageFileModel, filesPath path.Path, diags *diag.Diagnostics) {
+	for filePath, file := range files {
+		for _, part := range strings.Split(filePath, "/") {
+			if part == ".." {
+				diags.AddAttributeError(filesPath.AtMapKey(filePath), "File path must be inside storage", "File path must be inside storage")
+				break
+			}
+		}
+		if !file.ContentBase64.IsNull() && !file.ContentBase64.IsUnknown() {
+			_, err := base64.StdEncoding.DecodeString(file.ContentBase64.ValueString())
+			if err != nil {
+				diags.AddAttributeError(filesPath.AtMapKey(filePath).AtName("content_base64"), "Invalid base64 content", "Invalid base64 content. "+err.Error())
+			}
+		}
+	}
+}
+
+// readStorageFiles returns only files which are present in storage and have the same content as in state
+func readStorageFiles(ctx context.Context, client *dokkuclient.Client, storageName string, files map[string]storageFileModel) (map[string]storageFileModel, error) {
+	var paths []string
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	checksums, err := client.StorageFilesChecksums(ctx, storageName, paths)
+	if err != nil {
+		return files, err
+	}
+
+	res := make(map[string]storageFileModel)
+	for filePath, file := range files {
+		content, err := storageFileContent(file)
+		if err != nil {
+			continue
+		}
+		if checksums[filePath] == fmt.Sprintf("%x", sha256.Sum256(content)) {
+			res[filePath] = file
+		}
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return res, nil
+}
+
+// syncStorageFiles uploads new and changed files and removes files not present in plan
+func syncStorageFiles(ctx context.Context, client *dokkuclient.Client, storageName string, planFiles map[string]storageFileModel, stateFiles map[string]storageFileModel) (changed bool, err error) {
+	var pathsToRemove []string
+	for filePath := range stateFiles {
+		if _, ok := planFiles[filePath]; !ok {
+			pathsToRemove = append(pathsToRemove, filePath)
+		}
+	}
+
+	var filesToUpload []dokkuclient.StorageFile
+	for filePath, planFile := range planFiles {
+		if stateFile, ok := stateFiles[filePath]; ok {
+			if stateFile.Content.Equal(planFile.Content) && stateFile.ContentBase64.Equal(planFile.ContentBase64) && stateFile.Mode.Equal(planFile.Mode) && stateFile.Owner.Equal(planFile.Owner) {
+				continue
+			}
+		}
+
+		file, err := formatStorageFile(filePath, planFile)
+		if err != nil {
+			return false, err
+		}
+		filesToUpload = append(filesToUpload, file)
+	}
+
+	if len(filesToUpload) == 0 && len(pathsToRemove) == 0 {
+		return false, nil
+	}
+	return true, client.StorageFilesUpload(ctx, storageName, filesToUpload, pathsToRemove)
+}
+
+func storageFileContent(file storageFileModel) ([]byte, error) {
+	if !file.ContentBase64.IsNull() {
+		return base64.StdEncoding.DecodeString(file.ContentBase64.ValueString())
+	}
+	return []byte(file.Content.ValueString()), nil
+}
+
+func formatStorageFile(filePath string, file storageFileModel) (dokkuclient.StorageFile, error) {
+	content, err := storageFileContent(file)
+	if err != nil {
+		return dokkuclient.StorageFile{}, fmt.Errorf("unable to decode content of %s: %w", filePath, err)
+	}
+
+	mode := uint64(0644)
+	if !file.Mode.IsNull() {
+		mode, err = strconv.ParseUint(file.Mode.ValueString(), 8, 32)
+		if err != nil {
+			return dokkuclient.StorageFile{}, fmt.Errorf("unable to parse mode of %s: %w", filePath, err)
+		}
+	}
+
+	return dokkuclient.StorageFile{
+		Path:    filePath,
+		Content: content,
+		Mode:    os.FileMode(mode),
+		Owner:   file.Owner.ValueString(),
+	}, nil
+}
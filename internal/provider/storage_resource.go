@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	dokkuclient "terraform-provider-dokku/internal/provider/dokku_client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &storageResource{}
+	_ resource.ResourceWithConfigure      = &storageResource{}
+	_ resource.ResourceWithImportState    = &storageResource{}
+	_ resource.ResourceWithValidateConfig = &storageResource{}
+)
+
+func NewStorageResource() resource.Resource {
+	return &storageResource{}
+}
+
+type storageResource struct {
+	client *dokkuclient.Client
+}
+
+type storageResourceModel struct {
+	Name            types.String                `tfsdk:"name"`
+	Chown           types.String                `tfsdk:"chown"`
+	LocalDirectory  types.String                `tfsdk:"local_directory"`
+	Files           map[string]storageFileModel `tfsdk:"files"`
+	DeleteOnDestroy types.Bool                  `tfsdk:"delete_on_destroy"`
+}
+
+// Metadata returns the resource type name.
+func (r *storageResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_storage"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *storageResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	//nolint:forcetypeassert
+	r.client = req.ProviderData.(*dokkuclient.Client)
+}
+
+// Schema defines the schema for the resource.
+func (r *storageResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of storage directory. Directory will be created in /var/lib/dokku/data/storage. Use the same name in dokku_app.storage to mount it to app",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`), "invalid name"),
+				},
+			},
+			"chown": schema.StringAttribute{
+				Optional: true,
+				Description: strings.Join([]string{
+					"Chown option for storage directory. Allowed values: herokuish, heroku, packeto, root. Default: herokuish",
+					"",
+					"Note that dokku_app.storage ensures directory with default chown option.",
+				}, "\n"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("herokuish", "heroku", "packeto", "root"),
+				},
+			},
+			"local_directory": schema.StringAttribute{
+				Optional: true,
+				Description: strings.Join([]string{
+					"Uploads local directory to host (always, without checking is it changed)",
+					"",
+					"Should not be used for uploading large files, because it is slow.",
+					"Also see upload_* attributes in provider configuration.",
+				}, "\n"),
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"files": storageFilesSchemaAttribute(),
+			"delete_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Remove all content of storage directory on destroy. Empty directory itself is left on host. Default: false",
+			},
+		},
+	}
+}
+
+func (r *storageResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data storageResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateStorageFiles(data.Files, path.Root("files"), &resp.Diagnostics)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *storageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state storageResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// existence of directory and its chown -- unable to read without helper app
+
+	if len(state.Files) != 0 {
+		files, err := readStorageFiles(ctx, r.client, state.Name.ValueString(), state.Files)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("files"), "Unable to get storage files", "Unable to get storage files. "+err.Error())
+			return
+		}
+		state.Files = files
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *storageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan storageResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.StorageEnsure(ctx, plan.Name.ValueString(), plan.Chown.ValueString(), plan.LocalDirectory.ValueStringPointer())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to ensure storage", "Unable to ensure storage. "+err.Error())
+		return
+	}
+
+	_, err = syncStorageFiles(ctx, r.client, plan.Name.ValueString(), plan.Files, nil)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("files"), "Unable to upload storage files", "Unable to upload storage files. "+err.Error())
+		return
+	}
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *storageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan storageResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var state storageResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Name.ValueString() != state.Name.ValueString() {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Name can't be changed", "Name can't be changed")
+		return
+	}
+	name := plan.Name.ValueString()
+
+	existingFiles := state.Files
+	if !plan.Chown.Equal(state.Chown) || !plan.LocalDirectory.IsNull() {
+		err := r.client.StorageEnsure(ctx, name, plan.Chown.ValueString(), plan.LocalDirectory.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to ensure storage", "Unable to ensure storage. "+err.Error())
+			return
+		}
+
+		// ownership of all files was restored - upload all files again
+		existingFiles = nil
+	}
+
+	_, err := syncStorageFiles(ctx, r.client, name, plan.Files, existingFiles)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("files"), "Unable to upload storage files", "Unable to upload storage files. "+err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *storageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state storageResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keep directory with data by default
+	if !state.DeleteOnDestroy.ValueBool() {
+		return
+	}
+
+	err := r.client.StorageDestroy(ctx, state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to destroy storage", "Unable to destroy storage. "+err.Error())
+		return
+	}
+	resp.Diagnostics.AddWarning("Storage directory is left on host",
+		"Content of storage is removed, but empty directory "+state.Name.ValueString()+" is left on host. Dokku has no command to remove it, and helper app has only storage directory mounted.")
+}
+
+func (r *storageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to name attribute
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+}